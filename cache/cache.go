@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultSize 未指定大小时的最大缓存条目数
+const DefaultSize = 1024
+
+// Cache 缓存后端，实现需要保证并发安全
+type Cache interface {
+	// Get 获得缓存的值
+	Get(key string) (interface{}, bool)
+	// Put 缓存值
+	Put(key string, value interface{})
+	// Clear 清空所有缓存
+	Clear()
+}
+
+// Factory 创建namespace使用的缓存，size为最大条目数，ttl为过期时间，0表示不过期
+type Factory func(namespace string, size int, ttl time.Duration) Cache
+
+// DefaultFactory 使用进程内的LRU缓存
+func DefaultFactory(namespace string, size int, ttl time.Duration) Cache {
+	return NewLRU(size, ttl)
+}
+
+type lruEntry struct {
+	key      string
+	value    interface{}
+	expireAt time.Time
+}
+
+type lruCache struct {
+	size  int
+	ttl   time.Duration
+	items map[string]*list.Element
+	ll    *list.List
+	lock  sync.Mutex
+}
+
+// NewLRU 创建LRU缓存，超过size时淘汰最久未使用的条目，ttl大于0时条目在ttl之后过期
+func NewLRU(size int, ttl time.Duration) Cache {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &lruCache{
+		size:  size,
+		ttl:   ttl,
+		items: map[string]*list.Element{},
+		ll:    list.New(),
+	}
+}
+
+func (c *lruCache) Get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruEntry)
+	if c.ttl > 0 && time.Now().After(entry.expireAt) {
+		c.ll.Remove(e)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return entry.value, true
+}
+
+func (c *lruCache) Put(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var expireAt time.Time
+	if c.ttl > 0 {
+		expireAt = time.Now().Add(c.ttl)
+	}
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value = value
+		entry.expireAt = expireAt
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expireAt: expireAt})
+	for c.ll.Len() > c.size {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.items, e.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.items = map[string]*list.Element{}
+	c.ll.Init()
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU(2, 0)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expect a")
+	}
+	c.Put("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b should be evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatal("expect a = 1 but get ", v)
+	}
+	c.Clear()
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expect empty after clear")
+	}
+}
+
+func TestLRUExpire(t *testing.T) {
+	c := NewLRU(0, 10*time.Millisecond)
+	c.Put("a", 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expect a")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a should be expired")
+	}
+}
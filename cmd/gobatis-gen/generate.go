@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type Options struct {
+	// Package 生成代码的包名
+	Package string
+	// Imports parameterType以及resultType中引用的包
+	Imports []string
+}
+
+// typeAliases parameterType以及resultType中可以使用的类型别名
+var typeAliases = map[string]string{
+	"int":     "int",
+	"integer": "int",
+	"long":    "int64",
+	"short":   "int16",
+	"byte":    "byte",
+	"float":   "float32",
+	"double":  "float64",
+	"boolean": "bool",
+	"string":  "string",
+	"map":     "map[string]interface{}",
+	"hashmap": "map[string]interface{}",
+}
+
+type methodData struct {
+	Name       string
+	Const      string
+	SqlId      string
+	Action     string
+	Runner     string
+	ParamType  string
+	ResultType string
+}
+
+type mapperData struct {
+	Options
+	Namespace string
+	Sources   string
+	Mapper    string
+	Methods   []methodData
+}
+
+var mapperTemplate = template.Must(template.New("mapper").Parse(`// Code generated by gobatis-gen. DO NOT EDIT.
+// source: {{.Sources}}
+
+package {{.Package}}
+
+import (
+	"context"
+{{range .Imports}}	"{{.}}"
+{{end}}
+	gobatis "github.com/xfali/gobatis/v2/runner/v1"
+)
+
+const (
+{{- range .Methods}}
+	{{.Const}} = "{{.SqlId}}"
+{{- end}}
+)
+
+// {{.Mapper}} namespace {{.Namespace}}
+type {{.Mapper}} struct {
+	sess *gobatis.Session
+}
+
+func New{{.Mapper}}(sess *gobatis.Session) *{{.Mapper}} {
+	return &{{.Mapper}}{sess: sess}
+}
+{{range .Methods}}{{$params := "params ...interface{}"}}{{$args := "params..."}}{{if .ParamType}}{{$params = printf "param %s" .ParamType}}{{$args = "param"}}{{end}}
+{{if eq .Action "select"}}{{if .ResultType}}
+// {{.Name}} select {{.SqlId}}
+func (m *{{$.Mapper}}) {{.Name}}(ctx context.Context, {{$params}}) ([]{{.ResultType}}, error) {
+	var ret []{{.ResultType}}
+	err := m.sess.Select({{.Const}}).Context(ctx).Param({{$args}}).Result(&ret)
+	return ret, err
+}
+{{else}}
+// {{.Name}} select {{.SqlId}}，结果写入result
+func (m *{{$.Mapper}}) {{.Name}}(ctx context.Context, result interface{}, {{$params}}) error {
+	return m.sess.Select({{.Const}}).Context(ctx).Param({{$args}}).Result(result)
+}
+{{end}}{{else if eq .Action "insert"}}
+// {{.Name}} insert {{.SqlId}}，返回最后插入的自增id，数据库不支持获取自增id时返回0
+func (m *{{$.Mapper}}) {{.Name}}(ctx context.Context, {{$params}}) (int64, error) {
+	return gobatis.Insert(ctx, m.sess, {{.Const}}, {{$args}})
+}
+{{else}}
+// {{.Name}} {{.Action}} {{.SqlId}}，返回影响的行数
+func (m *{{$.Mapper}}) {{.Name}}(ctx context.Context, {{$params}}) (int64, error) {
+	var count int64
+	err := m.sess.{{.Runner}}({{.Const}}).Context(ctx).Param({{$args}}).Result(&count)
+	return count, err
+}
+{{end}}{{end}}`))
+
+// generate 生成namespace对应的mapper代码
+func generate(ns *Namespace, opts Options) ([]byte, error) {
+	data := mapperData{
+		Options:   opts,
+		Namespace: ns.Name,
+		Sources:   strings.Join(ns.Sources, ", "),
+		Mapper:    mapperName(ns.Name),
+	}
+	// ids 方法名对应的语句id，不同的id可能转换为相同的方法名，如select_user与selectUser
+	ids := map[string]string{}
+	for _, stmt := range ns.Statements {
+		name := exportedName(stmt.Id)
+		if id, ok := ids[name]; ok {
+			return nil, fmt.Errorf("sql id %s and %s generate the same method %s", id, stmt.SqlId, name)
+		}
+		ids[name] = stmt.SqlId
+		m := methodData{
+			Name:       name,
+			Const:      data.Mapper + name,
+			SqlId:      stmt.SqlId,
+			Action:     stmt.Action,
+			ParamType:  goType(stmt.ParameterType),
+			ResultType: goType(stmt.ResultType),
+		}
+		switch stmt.Action {
+		case "select", "insert":
+		case "update":
+			m.Runner = "Update"
+		case "delete":
+			m.Runner = "Delete"
+		default:
+			m.Action = "exec"
+			m.Runner = "Exec"
+		}
+		data.Methods = append(data.Methods, m)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := mapperTemplate.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// fileName 生成代码的文件名
+func fileName(ns *Namespace) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, ns.Name)) + "_mapper.go"
+}
+
+// mapperName 使用namespace的最后一段作为mapper名称
+func mapperName(ns string) string {
+	if i := strings.LastIndex(ns, "."); i >= 0 {
+		ns = ns[i+1:]
+	}
+	name := exportedName(ns)
+	if !strings.HasSuffix(name, "Mapper") {
+		name += "Mapper"
+	}
+	return name
+}
+
+// exportedName 将id转换为导出的驼峰名称，非字母数字的字符作为分隔符
+func exportedName(id string) string {
+	b := strings.Builder{}
+	upper := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteRune('X')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func goType(t string) string {
+	if v, ok := typeAliases[strings.ToLower(t)]; ok {
+		return v
+	}
+	return t
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testXml = `<mapper namespace="test.user">
+	<select id="selectUser" parameterType="*model.User" resultType="model.User">SELECT * FROM tbl_user</select>
+	<select id="selectAny">SELECT * FROM tbl_user</select>
+	<insert id="insert_user" parameterType="*model.User">INSERT INTO tbl_user(name) VALUES(#{name})</insert>
+	<delete id="deleteUser" parameterType="long">DELETE FROM tbl_user WHERE id = #{0}</delete>
+</mapper>`
+
+const testTpl = `{{define "namespace"}}test.order{{end}}
+{{define "updateOrder"}}UPDATE tbl_order SET state = {{arg .State}}{{end}}
+{{define "selectOrder"}}SELECT * FROM tbl_order{{end}}`
+
+func TestGenerate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gobatis-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "user.xml"), []byte(testXml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "order.tpl"), []byte(testTpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "mapper")
+	err = run([]string{dir}, out, Options{Package: "mapper", Imports: []string{"github.com/x/model"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := ioutil.ReadFile(filepath.Join(out, "test_user_mapper.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`UserMapperSelectUser = "test.user.selectUser"`,
+		`UserMapperInsertUser = "test.user.insert_user"`,
+		`"github.com/x/model"`,
+		"func (m *UserMapper) SelectUser(ctx context.Context, param *model.User) ([]model.User, error)",
+		"func (m *UserMapper) SelectAny(ctx context.Context, result interface{}, params ...interface{}) error",
+		"func (m *UserMapper) InsertUser(ctx context.Context, param *model.User) (int64, error)",
+		"gobatis.Insert(ctx, m.sess, UserMapperInsertUser, param)",
+		"func (m *UserMapper) DeleteUser(ctx context.Context, param int64) (int64, error)",
+		"m.sess.Delete(UserMapperDeleteUser)",
+	} {
+		if !strings.Contains(string(user), s) {
+			t.Fatalf("expect %s in:\n%s", s, user)
+		}
+	}
+
+	order, err := ioutil.ReadFile(filepath.Join(out, "test_order_mapper.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`OrderMapperUpdateOrder = "test.order.updateOrder"`,
+		"func (m *OrderMapper) UpdateOrder(ctx context.Context, params ...interface{}) (int64, error)",
+		"m.sess.Update(OrderMapperUpdateOrder)",
+		"func (m *OrderMapper) SelectOrder(ctx context.Context, result interface{}, params ...interface{}) error",
+	} {
+		if !strings.Contains(string(order), s) {
+			t.Fatalf("expect %s in:\n%s", s, order)
+		}
+	}
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	ns := &Namespace{
+		Name: "test.user",
+		Statements: []Statement{
+			{Id: "select_user", SqlId: "test.user.select_user", Action: "select"},
+			{Id: "selectUser", SqlId: "test.user.selectUser", Action: "select"},
+		},
+	}
+	_, err := generate(ns, Options{Package: "mapper"})
+	if err == nil || !strings.Contains(err.Error(), "test.user.select_user") || !strings.Contains(err.Error(), "test.user.selectUser") {
+		t.Fatal("expect name collision error but get ", err)
+	}
+}
+
+func TestGenerateMapperCollision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gobatis-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, ns := range []string{"a.user", "b.user"} {
+		xml := `<mapper namespace="` + ns + `"><select id="selectUser">SELECT * FROM tbl_user</select></mapper>`
+		if err := ioutil.WriteFile(filepath.Join(dir, ns+".xml"), []byte(xml), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = run([]string{dir}, filepath.Join(dir, "mapper"), Options{Package: "mapper"})
+	if err == nil || !strings.Contains(err.Error(), "a.user") || !strings.Contains(err.Error(), "b.user") {
+		t.Fatal("expect mapper collision error but get ", err)
+	}
+}
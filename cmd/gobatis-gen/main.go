@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// gobatis-gen 根据mapper xml以及tpl文件生成类型化的mapper代码
+//
+// 用法：
+//
+//	gobatis-gen -pkg mapper -out ./mapper [-import github.com/x/model] mapper_dir_or_file...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+func main() {
+	var (
+		opts    Options
+		out     string
+		imports stringsFlag
+	)
+	flag.StringVar(&opts.Package, "pkg", "mapper", "package name of generated code")
+	flag.StringVar(&out, "out", ".", "output directory")
+	flag.Var(&imports, "import", "import path used by parameterType or resultType, can be repeated")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] mapper_dir_or_file...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts.Imports = imports
+
+	if err := run(flag.Args(), out, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string, out string, opts Options) error {
+	nss, err := loadNamespaces(paths)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+	for _, ns := range nss {
+		data, err := generate(ns, opts)
+		if err != nil {
+			return fmt.Errorf("generate namespace %s failed: %v", ns.Name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(out, fileName(ns)), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
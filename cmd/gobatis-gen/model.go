@@ -0,0 +1,198 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/xfali/gobatis/v2/parsing/template"
+	"github.com/xfali/gobatis/v2/parsing/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Namespace 一个namespace下的所有语句，对应生成的一个mapper
+type Namespace struct {
+	Name       string
+	Sources    []string
+	Statements []Statement
+}
+
+type Statement struct {
+	Id string
+	// SqlId 注册到gobatis中的完整id
+	SqlId         string
+	Action        string
+	ParameterType string
+	ResultType    string
+}
+
+// loadNamespaces 读取mapper文件或者目录中的xml以及tpl文件，按照namespace合并语句
+func loadNamespaces(paths []string) ([]*Namespace, error) {
+	files, err := collectFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	nsMap := map[string]*Namespace{}
+	for _, file := range files {
+		name, stmts, err := loadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+		ns, ok := nsMap[name]
+		if !ok {
+			ns = &Namespace{Name: name}
+			nsMap[name] = ns
+		}
+		ns.Sources = append(ns.Sources, filepath.Base(file))
+		for _, stmt := range stmts {
+			for _, v := range ns.Statements {
+				if v.SqlId == stmt.SqlId {
+					return nil, fmt.Errorf("%s: sql id %s is duplicated", file, stmt.SqlId)
+				}
+			}
+			ns.Statements = append(ns.Statements, stmt)
+		}
+	}
+
+	ret := make([]*Namespace, 0, len(nsMap))
+	for _, ns := range nsMap {
+		ret = append(ret, ns)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	// mapper名称只使用namespace的最后一段，不同namespace可能生成相同的mapper，如a.user与b.user
+	mappers := map[string]string{}
+	for _, ns := range ret {
+		name := mapperName(ns.Name)
+		if other, ok := mappers[name]; ok {
+			return nil, fmt.Errorf("namespace %s and %s generate the same mapper %s", other, ns.Name, name)
+		}
+		mappers[name] = ns.Name
+	}
+	return ret, nil
+}
+
+func collectFiles(paths []string) ([]string, error) {
+	var ret []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			ret = append(ret, path)
+			continue
+		}
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && isMapperFile(file) {
+				ret = append(ret, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func isMapperFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xml", ".tpl":
+		return true
+	}
+	return false
+}
+
+func loadFile(file string) (string, []Statement, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xml":
+		mapper, err := xml.ParseFile(file)
+		if err != nil {
+			return "", nil, err
+		}
+		return loadXml(mapper)
+	case ".tpl":
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", nil, err
+		}
+		return loadTemplate(data)
+	}
+	return "", nil, fmt.Errorf("unsupported mapper file")
+}
+
+func loadXml(mapper *xml.Mapper) (string, []Statement, error) {
+	ns := strings.TrimSpace(mapper.Namespace)
+	var ret []Statement
+	add := func(id, action, paramType, resultType string) {
+		ret = append(ret, Statement{
+			Id:            id,
+			SqlId:         sqlId(ns, id),
+			Action:        action,
+			ParameterType: strings.TrimSpace(paramType),
+			ResultType:    strings.TrimSpace(resultType),
+		})
+	}
+	for _, v := range mapper.Select {
+		add(v.Id, "select", v.ParameterType, v.ResultType)
+	}
+	for _, v := range mapper.Insert {
+		add(v.Id, "insert", v.ParameterType, "")
+	}
+	for _, v := range mapper.Update {
+		add(v.Id, "update", v.ParameterType, "")
+	}
+	for _, v := range mapper.Delete {
+		add(v.Id, "delete", v.ParameterType, "")
+	}
+	return ns, ret, nil
+}
+
+func loadTemplate(data []byte) (string, []Statement, error) {
+	ns, stmts, err := template.ParseStatements(data)
+	if err != nil {
+		return "", nil, err
+	}
+	ret := make([]Statement, 0, len(stmts))
+	for _, v := range stmts {
+		ret = append(ret, Statement{
+			Id:     v.Id,
+			SqlId:  sqlId(ns, v.Id),
+			Action: v.Action,
+		})
+	}
+	return ns, ret, nil
+}
+
+func sqlId(ns, id string) string {
+	if ns == "" {
+		return id
+	}
+	return ns + "." + id
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package factory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/xfali/lean/drivers/sqldrv"
+	"github.com/xfali/lean/executor"
+	"github.com/xfali/lean/session"
+	"github.com/xfali/lean/statement"
+)
+
+// sqlConnection 同lean的sqldrv连接，创建的session额外支持Prepare
+type sqlConnection struct {
+	db             *sql.DB
+	driverName     string
+	dataSourceName string
+}
+
+func newSqlConnection(driverName, dataSourceName string) *sqlConnection {
+	return &sqlConnection{
+		driverName:     driverName,
+		dataSourceName: dataSourceName,
+	}
+}
+
+func (c *sqlConnection) Open() error {
+	db, err := sql.Open(c.driverName, c.dataSourceName)
+	if err != nil {
+		return fmt.Errorf("Open %s failed: %v ", c.driverName, err)
+	}
+	c.db = db
+	return nil
+}
+
+func (c *sqlConnection) GetSession() (session.Session, error) {
+	if c.db == nil {
+		return nil, errors.New("Connection not opened ")
+	}
+	trans := newSqlTransaction(c.db)
+	sess := sqldrv.NewSqlSession(c.db, sqldrv.SessOpts.SetExecutorFactory(func(db *sql.DB) (executor.Executor, error) {
+		return executor.NewSimpleExecutor(trans), nil
+	}))
+	return &sqlSession{
+		Session: sess,
+		trans:   trans,
+	}, nil
+}
+
+func (c *sqlConnection) Close() error {
+	if c.db != nil {
+		return c.db.Close()
+	}
+	return nil
+}
+
+type sqlSession struct {
+	session.Session
+	trans *sqlTransaction
+}
+
+// Prepare 预编译语句，事务中使用当前事务预编译
+func (s *sqlSession) Prepare(ctx context.Context, sql string) (statement.Statement, error) {
+	return s.trans.GetHandler().Prepare(ctx, sql)
+}
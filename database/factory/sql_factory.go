@@ -19,7 +19,6 @@ package factory
 
 import (
 	"github.com/xfali/lean/connection"
-	"github.com/xfali/lean/drivers/sqldrv"
 	"github.com/xfali/xlog"
 )
 
@@ -41,5 +40,5 @@ func (f *SqlFactory) GetDriverName() string {
 }
 
 func (f *SqlFactory) CreateConnection() connection.Connection {
-	return sqldrv.NewSqlConnection(f.driverName, f.dsInfo)
+	return newSqlConnection(f.driverName, f.dsInfo)
 }
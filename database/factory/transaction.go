@@ -0,0 +1,220 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package factory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/xfali/lean/drivers/sqldrv"
+	"github.com/xfali/lean/errors"
+	"github.com/xfali/lean/handler"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/lean/statement"
+	"sync"
+)
+
+type txOptionsKey struct{}
+
+// WithTxOptions 将事务选项放入context，SqlFactory创建的连接开启事务时会使用该选项
+func WithTxOptions(ctx context.Context, opts *sql.TxOptions) context.Context {
+	return context.WithValue(ctx, txOptionsKey{}, opts)
+}
+
+// GetTxOptions 获得context中的事务选项，不存在时返回nil
+func GetTxOptions(ctx context.Context) *sql.TxOptions {
+	if ctx == nil {
+		return nil
+	}
+	if opts, ok := ctx.Value(txOptionsKey{}).(*sql.TxOptions); ok {
+		return opts
+	}
+	return nil
+}
+
+// sqlTransaction 开启事务时使用context中的事务选项
+type sqlTransaction struct {
+	db     *sql.DB
+	tx     *sql.Tx
+	locker sync.Mutex
+}
+
+func newSqlTransaction(db *sql.DB) *sqlTransaction {
+	return &sqlTransaction{
+		db: db,
+	}
+}
+
+func (trans *sqlTransaction) GetHandler() handler.Handler {
+	trans.locker.Lock()
+	defer trans.locker.Unlock()
+
+	if trans.tx == nil {
+		return &sqlHandler{exec: trans.db}
+	}
+	return &sqlHandler{exec: trans.tx}
+}
+
+func (trans *sqlTransaction) Close() error {
+	trans.locker.Lock()
+	tx := trans.tx
+	trans.tx = nil
+	trans.locker.Unlock()
+
+	if tx != nil {
+		return tx.Rollback()
+	}
+	return nil
+}
+
+func (trans *sqlTransaction) Ping(ctx context.Context) bool {
+	return trans.db.PingContext(ctx) == nil
+}
+
+func (trans *sqlTransaction) Begin(ctx context.Context, successCallback func(handler.Handler) error) error {
+	trans.locker.Lock()
+	if trans.tx != nil {
+		trans.locker.Unlock()
+		return errors.TransactionHaveBegin
+	}
+	tx, err := trans.db.BeginTx(ctx, GetTxOptions(ctx))
+	if err != nil {
+		trans.locker.Unlock()
+		return wrapError(errors.TransactionBeginError, err)
+	}
+	trans.tx = tx
+	trans.locker.Unlock()
+
+	if successCallback != nil {
+		return successCallback(&sqlHandler{exec: tx})
+	}
+	return nil
+}
+
+func (trans *sqlTransaction) Commit(ctx context.Context, successCallback func(handler.Handler) error) error {
+	return trans.finish(successCallback, (*sql.Tx).Commit, func(err error) error {
+		return wrapError(errors.TransactionCommitError, err)
+	})
+}
+
+func (trans *sqlTransaction) Rollback(ctx context.Context, successCallback func(handler.Handler) error) error {
+	return trans.finish(successCallback, (*sql.Tx).Rollback, func(err error) error {
+		return wrapError(errors.TransactionRollbackError, err)
+	})
+}
+
+// finish 提交或者回滚事务，失败时使用wrap包装错误
+func (trans *sqlTransaction) finish(successCallback func(handler.Handler) error, f func(tx *sql.Tx) error, wrap func(err error) error) error {
+	trans.locker.Lock()
+	tx := trans.tx
+	trans.tx = nil
+	trans.locker.Unlock()
+
+	if tx == nil {
+		return errors.TransactionWithoutBegin
+	}
+	if err := f(tx); err != nil {
+		return wrap(err)
+	}
+	if successCallback != nil {
+		return successCallback(&sqlHandler{exec: tx})
+	}
+	return nil
+}
+
+// wrapError 在错误信息前附加lean的错误码，原始错误可以通过errors.Is获得
+// 不使用lean错误码的Format方法，该方法会修改全局的错误变量
+func wrapError(code error, err error) error {
+	return fmt.Errorf("%v: %w", code, err)
+}
+
+// sqlExecutor *sql.DB以及*sql.Tx的公共方法
+type sqlExecutor interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type sqlHandler struct {
+	exec sqlExecutor
+}
+
+func (h *sqlHandler) Prepare(ctx context.Context, sqlStr string) (statement.Statement, error) {
+	stmt, err := h.exec.PrepareContext(ctx, sqlStr)
+	if err != nil {
+		return nil, wrapError(errors.ConnectionPrepareError, err)
+	}
+	return &sqlStatement{stmt: stmt}, nil
+}
+
+func (h *sqlHandler) Query(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	rows, err := h.exec.QueryContext(ctx, stmt, params...)
+	if err != nil {
+		return nil, wrapError(errors.HandlerQueryError, err)
+	}
+	return newSqlQueryResult(rows), nil
+}
+
+func (h *sqlHandler) Execute(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	ret, err := h.exec.ExecContext(ctx, stmt, params...)
+	if err != nil {
+		return nil, wrapError(errors.HandlerExecuteError, err)
+	}
+	return sqldrv.NewSqlExecResultSet(ret), nil
+}
+
+type sqlStatement struct {
+	stmt *sql.Stmt
+}
+
+func (s *sqlStatement) Query(ctx context.Context, params ...interface{}) (resultset.Result, error) {
+	rows, err := s.stmt.QueryContext(ctx, params...)
+	if err != nil {
+		return nil, wrapError(errors.StatementQueryError, err)
+	}
+	return newSqlQueryResult(rows), nil
+}
+
+func (s *sqlStatement) Execute(ctx context.Context, params ...interface{}) (resultset.Result, error) {
+	ret, err := s.stmt.ExecContext(ctx, params...)
+	if err != nil {
+		return nil, wrapError(errors.StatementExecError, err)
+	}
+	return sqldrv.NewSqlExecResultSet(ret), nil
+}
+
+func (s *sqlStatement) Close() error {
+	return s.stmt.Close()
+}
+
+// sqlQueryResult 额外暴露*sql.Rows的Err，用于获得迭代过程中的错误
+type sqlQueryResult struct {
+	resultset.Result
+	rows *sql.Rows
+}
+
+func newSqlQueryResult(rows *sql.Rows) *sqlQueryResult {
+	return &sqlQueryResult{
+		Result: sqldrv.NewSqlQueryResultSet(rows),
+		rows:   rows,
+	}
+}
+
+func (r *sqlQueryResult) Err() error {
+	return r.rows.Err()
+}
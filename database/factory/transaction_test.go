@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package factory
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	leanerrors "github.com/xfali/lean/errors"
+	"strings"
+	"testing"
+)
+
+var errFakeDriver = errors.New("fake driver error")
+
+// failDriver 所有Prepare及Begin均失败的驱动
+type failDriver struct{}
+
+func (failDriver) Open(name string) (driver.Conn, error) {
+	return failConn{}, nil
+}
+
+type failConn struct{}
+
+func (failConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errFakeDriver
+}
+
+func (failConn) Close() error {
+	return nil
+}
+
+func (failConn) Begin() (driver.Tx, error) {
+	return nil, errFakeDriver
+}
+
+func init() {
+	sql.Register("gobatis_factory_fail", failDriver{})
+}
+
+func TestSqlFactoryErrorCode(t *testing.T) {
+	conn := NewSqlFactory("gobatis_factory_fail", "").CreateConnection()
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	t.Run("query", func(t *testing.T) {
+		sess, err := conn.GetSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sess.Close()
+		_, err = sess.Query(ctx, "select 1")
+		if err == nil || !strings.HasPrefix(err.Error(), "26001 - ") {
+			t.Fatalf("expect HandlerQueryError, got %v", err)
+		}
+		if !errors.Is(err, errFakeDriver) {
+			t.Fatalf("expect driver error as cause, got %v", err)
+		}
+		// 再次失败时错误信息不能累加，全局的错误码保持不变
+		_, err = sess.Query(ctx, "select 2")
+		if strings.Count(err.Error(), errFakeDriver.Error()) != 1 {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if leanerrors.HandlerQueryError.Error() != "26001 - Connection prepare error" {
+			t.Fatalf("sentinel error modified: %v", leanerrors.HandlerQueryError)
+		}
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		sess, err := conn.GetSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sess.Close()
+		_, err = sess.Execute(ctx, "delete from t")
+		if err == nil || !strings.HasPrefix(err.Error(), "26002 - ") {
+			t.Fatalf("expect HandlerExecuteError, got %v", err)
+		}
+	})
+
+	t.Run("begin", func(t *testing.T) {
+		sess, err := conn.GetSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sess.Close()
+		err = sess.Begin(ctx)
+		if err == nil || !strings.HasPrefix(err.Error(), "22004 - ") || !errors.Is(err, errFakeDriver) {
+			t.Fatalf("expect TransactionBeginError, got %v", err)
+		}
+	})
+}
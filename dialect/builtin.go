@@ -0,0 +1,322 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dialect
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	defaultSavepointSyntax = SavepointSyntax{
+		Create:   "SAVEPOINT %s",
+		Rollback: "ROLLBACK TO SAVEPOINT %s",
+		Release:  "RELEASE SAVEPOINT %s",
+	}
+
+	// MySQL ?占位符，LIMIT分页，ON DUPLICATE KEY UPDATE
+	MySQL Dialect = &builtin{
+		name:        "mysql",
+		placeholder: questionPlaceholder,
+		quote:       [2]string{"`", "`"},
+		paginate:    limitOffset,
+		keyMode:     KeyModeFirstInsertId,
+		upsert:      onDuplicateKey,
+		savepoint:   defaultSavepointSyntax,
+	}
+
+	// Postgres $n占位符，LIMIT分页，RETURNING，ON CONFLICT
+	Postgres Dialect = &builtin{
+		name:        "postgres",
+		placeholder: dollarPlaceholder,
+		quote:       [2]string{`"`, `"`},
+		paginate:    limitOffset,
+		keyMode:     KeyModeReturning,
+		returning:   appendReturning,
+		upsert:      onConflict,
+		savepoint:   defaultSavepointSyntax,
+	}
+
+	// SQLite ?占位符，LIMIT分页，ON CONFLICT，RETURNING需要3.35及以上版本
+	SQLite Dialect = &builtin{
+		name:        "sqlite",
+		placeholder: questionPlaceholder,
+		quote:       [2]string{`"`, `"`},
+		paginate:    limitOffset,
+		keyMode:     KeyModeLastInsertId,
+		returning:   appendReturning,
+		upsert:      onConflict,
+		savepoint:   defaultSavepointSyntax,
+	}
+
+	// SQLServer @pn占位符，OFFSET FETCH分页，OUTPUT INSERTED，MERGE
+	SQLServer Dialect = &builtin{
+		name:        "sqlserver",
+		placeholder: atPlaceholder,
+		quote:       [2]string{"[", "]"},
+		paginate:    offsetFetch,
+		keyMode:     KeyModeOutput,
+		returning:   outputInserted,
+		upsert:      mergeSqlServer,
+		savepoint: SavepointSyntax{
+			Create:   "SAVE TRANSACTION %s",
+			Rollback: "ROLLBACK TRANSACTION %s",
+		},
+	}
+
+	// Oracle :n占位符，ROWNUM分页，MERGE
+	Oracle Dialect = &builtin{
+		name:        "oracle",
+		placeholder: colonPlaceholder,
+		quote:       [2]string{`"`, `"`},
+		paginate:    rownum,
+		keyMode:     KeyModeNone,
+		upsert:      mergeOracle,
+		savepoint: SavepointSyntax{
+			Create:   "SAVEPOINT %s",
+			Rollback: "ROLLBACK TO SAVEPOINT %s",
+		},
+	}
+
+	// ClickHouse ?占位符，LIMIT分页，不支持生成主键、upsert以及保存点
+	ClickHouse Dialect = &builtin{
+		name:        "clickhouse",
+		placeholder: questionPlaceholder,
+		quote:       [2]string{"`", "`"},
+		paginate:    limitOffset,
+		keyMode:     KeyModeNone,
+	}
+
+	orderByRegexp      = regexp.MustCompile(`(?i)\bORDER\s+BY\b`)
+	outputClauseRegexp = regexp.MustCompile(`(?i)\b(VALUES|SELECT|DEFAULT\s+VALUES)\b`)
+)
+
+type builtin struct {
+	name        string
+	placeholder func(index int) string
+	quote       [2]string
+	paginate    func(d Dialect, sql string, index int, offset, limit int64) (string, []interface{})
+	keyMode     KeyMode
+	returning   func(sql string, columns []string) (string, bool)
+	upsert      func(d Dialect, table string, columns, keys []string) (string, bool)
+	savepoint   SavepointSyntax
+}
+
+// WithPlaceholder 返回使用placeholder作为占位符的方言
+// 对于自定义方言只替换Placeholder方法，其他方法生成的占位符不受影响
+func WithPlaceholder(d Dialect, placeholder func(index int) string) Dialect {
+	if b, ok := d.(*builtin); ok {
+		ret := *b
+		ret.placeholder = placeholder
+		return &ret
+	}
+	return &placeholderDialect{Dialect: d, placeholder: placeholder}
+}
+
+type placeholderDialect struct {
+	Dialect
+	placeholder func(index int) string
+}
+
+func (d *placeholderDialect) Placeholder(index int) string {
+	return d.placeholder(index)
+}
+
+func (d *builtin) Name() string {
+	return d.name
+}
+
+func (d *builtin) Placeholder(index int) string {
+	return d.placeholder(index)
+}
+
+func (d *builtin) Quote(identifier string) string {
+	parts := strings.Split(identifier, ".")
+	for i, p := range parts {
+		parts[i] = d.quote[0] + strings.ReplaceAll(p, d.quote[1], d.quote[1]+d.quote[1]) + d.quote[1]
+	}
+	return strings.Join(parts, ".")
+}
+
+func (d *builtin) Paginate(sql string, index int, offset, limit int64) (string, []interface{}) {
+	return d.paginate(d, sql, index, offset, limit)
+}
+
+func (d *builtin) KeyMode() KeyMode {
+	return d.keyMode
+}
+
+func (d *builtin) Returning(sql string, columns []string) (string, bool) {
+	if d.returning == nil || len(columns) == 0 {
+		return sql, false
+	}
+	return d.returning(sql, columns)
+}
+
+func (d *builtin) Upsert(table string, columns, keys []string) (string, bool) {
+	if d.upsert == nil || len(columns) == 0 || len(keys) == 0 {
+		return "", false
+	}
+	return d.upsert(d, table, columns, keys)
+}
+
+func (d *builtin) Savepoint() SavepointSyntax {
+	return d.savepoint
+}
+
+func questionPlaceholder(int) string {
+	return "?"
+}
+
+func dollarPlaceholder(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+func colonPlaceholder(i int) string {
+	return ":" + strconv.Itoa(i)
+}
+
+func atPlaceholder(i int) string {
+	return "@p" + strconv.Itoa(i)
+}
+
+func limitOffset(d Dialect, sql string, index int, offset, limit int64) (string, []interface{}) {
+	return fmt.Sprintf("%s LIMIT %s OFFSET %s", sql, d.Placeholder(index), d.Placeholder(index+1)), []interface{}{limit, offset}
+}
+
+// offsetFetch 语句没有ORDER BY时自动添加
+func offsetFetch(d Dialect, sql string, index int, offset, limit int64) (string, []interface{}) {
+	if !orderByRegexp.MatchString(sql) {
+		sql += " ORDER BY (SELECT NULL)"
+	}
+	return fmt.Sprintf("%s OFFSET %s ROWS FETCH NEXT %s ROWS ONLY", sql, d.Placeholder(index), d.Placeholder(index+1)), []interface{}{offset, limit}
+}
+
+func rownum(d Dialect, sql string, index int, offset, limit int64) (string, []interface{}) {
+	return fmt.Sprintf("SELECT * FROM (SELECT gobatis_t_.*, ROWNUM gobatis_rn_ FROM (%s) gobatis_t_ WHERE ROWNUM <= %s) WHERE gobatis_rn_ > %s",
+		sql, d.Placeholder(index), d.Placeholder(index+1)), []interface{}{offset + limit, offset}
+}
+
+func appendReturning(sql string, columns []string) (string, bool) {
+	return strings.TrimRight(strings.TrimSpace(sql), ";") + " RETURNING " + strings.Join(columns, ", "), true
+}
+
+func outputInserted(sql string, columns []string) (string, bool) {
+	loc := outputClauseRegexp.FindStringIndex(sql)
+	if loc == nil {
+		return sql, false
+	}
+	return sql[:loc[0]] + "OUTPUT " + joinPrefix("INSERTED.", columns, ", ") + " " + sql[loc[0]:], true
+}
+
+func onDuplicateKey(d Dialect, table string, columns, keys []string) (string, bool) {
+	updates := updateColumns(columns, keys)
+	if len(updates) == 0 {
+		updates = keys[:1]
+	}
+	sets := make([]string, len(updates))
+	for i, c := range updates {
+		sets[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+	}
+	return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", insertSql(d, table, columns), strings.Join(sets, ", ")), true
+}
+
+func onConflict(d Dialect, table string, columns, keys []string) (string, bool) {
+	updates := updateColumns(columns, keys)
+	if len(updates) == 0 {
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", insertSql(d, table, columns), strings.Join(keys, ", ")), true
+	}
+	sets := make([]string, len(updates))
+	for i, c := range updates {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+	}
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", insertSql(d, table, columns), strings.Join(keys, ", "), strings.Join(sets, ", ")), true
+}
+
+func mergeSqlServer(d Dialect, table string, columns, keys []string) (string, bool) {
+	source := fmt.Sprintf("(VALUES (%s)) AS gobatis_s_ (%s)", placeholders(d, len(columns)), strings.Join(columns, ", "))
+	return merge(table+" AS gobatis_t_", source, columns, keys) + ";", true
+}
+
+func mergeOracle(d Dialect, table string, columns, keys []string) (string, bool) {
+	selects := make([]string, len(columns))
+	for i, c := range columns {
+		selects[i] = d.Placeholder(i+1) + " " + c
+	}
+	source := fmt.Sprintf("(SELECT %s FROM DUAL) gobatis_s_", strings.Join(selects, ", "))
+	return merge(table+" gobatis_t_", source, columns, keys), true
+}
+
+func merge(target, source string, columns, keys []string) string {
+	conds := make([]string, len(keys))
+	for i, k := range keys {
+		conds[i] = fmt.Sprintf("gobatis_t_.%s = gobatis_s_.%s", k, k)
+	}
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("MERGE INTO %s USING %s ON (%s)", target, source, strings.Join(conds, " AND ")))
+	if updates := updateColumns(columns, keys); len(updates) > 0 {
+		sets := make([]string, len(updates))
+		for i, c := range updates {
+			sets[i] = fmt.Sprintf("gobatis_t_.%s = gobatis_s_.%s", c, c)
+		}
+		b.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		b.WriteString(strings.Join(sets, ", "))
+	}
+	b.WriteString(fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(columns, ", "), joinPrefix("gobatis_s_.", columns, ", ")))
+	return b.String()
+}
+
+func insertSql(d Dialect, table string, columns []string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), placeholders(d, len(columns)))
+}
+
+func placeholders(d Dialect, n int) string {
+	ret := make([]string, n)
+	for i := range ret {
+		ret[i] = d.Placeholder(i + 1)
+	}
+	return strings.Join(ret, ", ")
+}
+
+// updateColumns 冲突时需要更新的列，即columns中不属于keys的列
+func updateColumns(columns, keys []string) []string {
+	var ret []string
+	for _, c := range columns {
+		isKey := false
+		for _, k := range keys {
+			if strings.EqualFold(c, k) {
+				isKey = true
+				break
+			}
+		}
+		if !isKey {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func joinPrefix(prefix string, values []string, sep string) string {
+	ret := make([]string, len(values))
+	for i, v := range values {
+		ret[i] = prefix + v
+	}
+	return strings.Join(ret, sep)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dialect
+
+import (
+	"sync"
+)
+
+// KeyMode 获取数据库生成主键的方式
+type KeyMode int
+
+const (
+	// KeyModeNone 不支持获取生成的主键
+	KeyModeNone KeyMode = iota
+	// KeyModeFirstInsertId 使用LastInsertId，多行插入时返回第一行的id，如mysql
+	KeyModeFirstInsertId
+	// KeyModeLastInsertId 使用LastInsertId，多行插入时返回最后一行的id，如sqlite
+	KeyModeLastInsertId
+	// KeyModeReturning 在语句末尾添加RETURNING子句，如postgresql
+	KeyModeReturning
+	// KeyModeOutput 在VALUES之前添加OUTPUT INSERTED子句，如sqlserver
+	KeyModeOutput
+)
+
+// SavepointSyntax 保存点语句格式，%s为保存点名称
+type SavepointSyntax struct {
+	// Create 为空表示数据库不支持保存点
+	Create   string
+	Rollback string
+	// Release 为空表示数据库不支持释放保存点，提交时随事务一起释放
+	Release string
+}
+
+// Dialect 数据库方言，描述不同数据库之间的语法差异
+type Dialect interface {
+	// Name 方言名称
+	Name() string
+	// Placeholder 第index个参数的占位符，index从1开始
+	Placeholder(index int) string
+	// Quote 转义标识符，包含.时分段转义
+	Quote(identifier string) string
+	// Paginate 将查询语句改写为分页语句，index为下一个占位符的序号
+	// 返回改写后的语句以及追加的参数
+	Paginate(sql string, index int, offset, limit int64) (string, []interface{})
+	// KeyMode 获取生成主键的方式
+	KeyMode() KeyMode
+	// Returning 为insert语句添加返回columns的子句，不支持时返回false
+	Returning(sql string, columns []string) (string, bool)
+	// Upsert 生成插入或者更新的语句，keys为判断冲突的列，参数按照columns的顺序绑定
+	// 表名以及列名原样输出，需要转义时先调用Quote，不支持时返回false
+	Upsert(table string, columns, keys []string) (string, bool)
+	// Savepoint 保存点语句格式
+	Savepoint() SavepointSyntax
+}
+
+var (
+	gDialectMap = map[string]Dialect{
+		"mysql":      MySQL,                                           //mysql
+		"postgres":   Postgres,                                        //postgresql
+		"pgx":        Postgres,                                        //postgresql
+		"sqlite3":    SQLite,                                          //sqlite
+		"sqlite":     SQLite,                                          //sqlite
+		"oci8":       Oracle,                                          //oracle
+		"godror":     Oracle,                                          //oracle
+		"adodb":      WithPlaceholder(SQLServer, questionPlaceholder), //sqlserver
+		"mssql":      SQLServer,                                       //sqlserver
+		"sqlserver":  SQLServer,                                       //sqlserver
+		"clickhouse": ClickHouse,                                      //clickhouse
+	}
+	gDialectLock sync.RWMutex
+)
+
+// Register 注册驱动对应的方言，返回是否覆盖了已有的方言
+func Register(driverName string, d Dialect) bool {
+	gDialectLock.Lock()
+	defer gDialectLock.Unlock()
+
+	_, ok := gDialectMap[driverName]
+	gDialectMap[driverName] = d
+	return ok
+}
+
+// Get 获得驱动对应的方言
+func Get(driverName string) (Dialect, bool) {
+	gDialectLock.RLock()
+	defer gDialectLock.RUnlock()
+
+	d, ok := gDialectMap[driverName]
+	return d, ok
+}
+
+// Select 获得驱动对应的方言，未注册时使用MySQL
+func Select(driverName string) Dialect {
+	if d, ok := Get(driverName); ok {
+		return d
+	}
+	return MySQL
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dialect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholder(t *testing.T) {
+	for driver, expect := range map[string]string{
+		"mysql":      "?",
+		"postgres":   "$2",
+		"sqlite3":    "?",
+		"oci8":       ":2",
+		"mssql":      "@p2",
+		"adodb":      "?",
+		"clickhouse": "?",
+		"unknown":    "?",
+	} {
+		if v := Select(driver).Placeholder(2); v != expect {
+			t.Fatalf("%s expect %s but get %s", driver, expect, v)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if v := MySQL.Quote("db.tbl`user"); v != "`db`.`tbl``user`" {
+		t.Fatal(v)
+	}
+	if v := SQLServer.Quote("dbo.user"); v != "[dbo].[user]" {
+		t.Fatal(v)
+	}
+	if v := Postgres.Quote("user"); v != `"user"` {
+		t.Fatal(v)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	sql, params := Postgres.Paginate("SELECT * FROM t WHERE a = $1", 2, 20, 10)
+	if sql != "SELECT * FROM t WHERE a = $1 LIMIT $2 OFFSET $3" || !reflect.DeepEqual(params, []interface{}{int64(10), int64(20)}) {
+		t.Fatal(sql, params)
+	}
+	sql, params = SQLServer.Paginate("SELECT * FROM t ORDER BY id", 1, 20, 10)
+	if sql != "SELECT * FROM t ORDER BY id OFFSET @p1 ROWS FETCH NEXT @p2 ROWS ONLY" || !reflect.DeepEqual(params, []interface{}{int64(20), int64(10)}) {
+		t.Fatal(sql, params)
+	}
+	sql, params = Oracle.Paginate("SELECT * FROM t", 1, 20, 10)
+	if sql != "SELECT * FROM (SELECT gobatis_t_.*, ROWNUM gobatis_rn_ FROM (SELECT * FROM t) gobatis_t_ WHERE ROWNUM <= :1) WHERE gobatis_rn_ > :2" ||
+		!reflect.DeepEqual(params, []interface{}{int64(30), int64(20)}) {
+		t.Fatal(sql, params)
+	}
+}
+
+func TestReturning(t *testing.T) {
+	if sql, ok := Postgres.Returning("INSERT INTO t(a) VALUES($1);", []string{"id"}); !ok || sql != "INSERT INTO t(a) VALUES($1) RETURNING id" {
+		t.Fatal(sql, ok)
+	}
+	if sql, ok := SQLServer.Returning("INSERT INTO t(a) VALUES(@p1)", []string{"id"}); !ok || sql != "INSERT INTO t(a) OUTPUT INSERTED.id VALUES(@p1)" {
+		t.Fatal(sql, ok)
+	}
+	if _, ok := MySQL.Returning("INSERT INTO t(a) VALUES(?)", []string{"id"}); ok {
+		t.Fatal("mysql should not support returning")
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	columns, keys := []string{"id", "name", "age"}, []string{"id"}
+	for d, expect := range map[Dialect]string{
+		MySQL:    "INSERT INTO t (id, name, age) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), age = VALUES(age)",
+		Postgres: "INSERT INTO t (id, name, age) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, age = EXCLUDED.age",
+		SQLServer: "MERGE INTO t AS gobatis_t_ USING (VALUES (@p1, @p2, @p3)) AS gobatis_s_ (id, name, age) ON (gobatis_t_.id = gobatis_s_.id)" +
+			" WHEN MATCHED THEN UPDATE SET gobatis_t_.name = gobatis_s_.name, gobatis_t_.age = gobatis_s_.age" +
+			" WHEN NOT MATCHED THEN INSERT (id, name, age) VALUES (gobatis_s_.id, gobatis_s_.name, gobatis_s_.age);",
+		Oracle: "MERGE INTO t gobatis_t_ USING (SELECT :1 id, :2 name, :3 age FROM DUAL) gobatis_s_ ON (gobatis_t_.id = gobatis_s_.id)" +
+			" WHEN MATCHED THEN UPDATE SET gobatis_t_.name = gobatis_s_.name, gobatis_t_.age = gobatis_s_.age" +
+			" WHEN NOT MATCHED THEN INSERT (id, name, age) VALUES (gobatis_s_.id, gobatis_s_.name, gobatis_s_.age)",
+	} {
+		sql, ok := d.Upsert("t", columns, keys)
+		if !ok || sql != expect {
+			t.Fatalf("%s expect:\n%s\nbut get:\n%s", d.Name(), expect, sql)
+		}
+	}
+	if sql, _ := SQLite.Upsert("t", []string{"id"}, []string{"id"}); sql != "INSERT INTO t (id) VALUES (?) ON CONFLICT (id) DO NOTHING" {
+		t.Fatal(sql)
+	}
+	if _, ok := ClickHouse.Upsert("t", columns, keys); ok {
+		t.Fatal("clickhouse should not support upsert")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	d := WithPlaceholder(Postgres, func(i int) string { return "?" })
+	if Register("test_driver", d) {
+		t.Fatal("test_driver should not be registered")
+	}
+	got := Select("test_driver")
+	if got.Placeholder(1) != "?" || got.Savepoint() != Postgres.Savepoint() {
+		t.Fatal("unexpected dialect ", got.Name())
+	}
+	if sql, _ := got.Paginate("SELECT 1", 1, 0, 1); sql != "SELECT 1 LIMIT ? OFFSET ?" {
+		t.Fatal(sql)
+	}
+}
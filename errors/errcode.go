@@ -37,22 +37,33 @@ var (
 	GetObjectInfoFailed         = gobatisError("11121", "Parse interface's info failed")
 	SqlIdDuplicates             = gobatisError("11205", "Sql id is duplicates")
 	DeserializeFailed           = gobatisError("11206", "Deserialize value failed")
+	TypeHandlerValueInvalid     = gobatisError("11207", "Type handler cannot convert value")
 	ParseSqlVarError            = gobatisError("12001", "SQL PARSE ERROR")
 	ParseSqlParamError          = gobatisError("12002", "SQL PARSE parameter error")
 	ParseSqlParamVarNumberError = gobatisError("12003", "SQL PARSE parameter var number error")
 	ParseParserNilError         = gobatisError("12004", "Dynamic sql parser is nil error")
+	TypeHandlerNotFound         = gobatisError("12005", "Type handler not found")
+	CallOutParamInvalid         = gobatisError("12006", "OUT parameter must be a pointer or a field of struct pointer")
+	StatementNotCallable        = gobatisError("12007", "Statement type is not CALLABLE")
 	ParseDynamicSqlError        = gobatisError("12010", "Parse dynamic sql error")
 	ParseTemplateNilError       = gobatisError("12101", "Parse template is nil")
 
-	ParseManagerDuplicates = gobatisError("15001", "Parsing manager support format is duplicates")
+	ParseManagerDuplicates      = gobatisError("15001", "Parsing manager support format is duplicates")
+	ResultMapRegistryNotSupport = gobatisError("15002", "Parser registry does not support result map")
 
 	ExecutorCommitError        = gobatisError("21001", "executor was closed when transaction commit")
 	ExecutorBeginError         = gobatisError("21002", "executor was closed when transaction begin")
 	ExecutorQueryError         = gobatisError("21003", "executor was closed when exec sql")
 	ExecutorGetConnectionError = gobatisError("21003", "executor get connection error")
+	ExecutorTimeout            = gobatisError("21004", "Statement execution timeout")
 	TransactionWithoutBegin    = gobatisError("22001", "Transaction without begin")
 	TransactionCommitError     = gobatisError("22002", "Transaction commit error")
 	TransactionBusinessError   = gobatisError("22003", "Business error in transaction")
+	SavepointNameInvalid       = gobatisError("22004", "Savepoint name is invalid")
+	TransactionRequired        = gobatisError("22005", "Transaction is required but not exist")
+	TransactionNotAllowed      = gobatisError("22006", "Transaction exists but not allowed")
+	TransactionReadOnly        = gobatisError("22007", "Cannot write in read-only transaction")
+	SavepointNotSupported      = gobatisError("22008", "Savepoint is not supported by dialect")
 	ConnectionPrepareError     = gobatisError("23001", "Connection prepare error")
 	StatementQueryError        = gobatisError("24001", "statement query error")
 	StatementExecError         = gobatisError("24002", "statement exec error")
@@ -64,6 +75,11 @@ var (
 	ResultNameNotFound         = gobatisError("31004", "result name not found")
 	ResultSelectEmptyValue     = gobatisError("31005", "select return empty value")
 	ResultSetValueFailed       = gobatisError("31006", "result set value failed")
+	ResultMapNotFound          = gobatisError("31007", "result map not found")
+	ResultMapInvalid           = gobatisError("31008", "result map does not match result type")
+	ConstructorInvalid         = gobatisError("31009", "constructor must be a function returning a value and an optional error")
+	ResultTooManyRows          = gobatisError("31010", "select return more than one row")
+	PageInvalid                = gobatisError("31011", "page num must be at least 1 and page size must be positive")
 )
 
 func gobatisError(code, message string) errCode {
@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	stderrors "errors"
+	"strings"
+)
+
+// Error 包含错误码、语句信息以及原始错误，使用errors.Is与对应的哨兵错误比较
+type Error struct {
+	code    string
+	message string
+	// SqlId 语句id，直接使用sql语句执行时为sql语句本身
+	SqlId string
+	// Sql 生成的sql语句，解析失败时为空
+	Sql string
+	// Driver 驱动名称
+	Driver string
+	// Cause 原始错误
+	Cause error
+}
+
+type coder interface {
+	Code() string
+	Message() string
+}
+
+// Code 错误码
+func (e errCode) Code() string {
+	return e.code
+}
+
+// Message 错误信息
+func (e errCode) Message() string {
+	return e.message
+}
+
+// Is 错误码以及错误信息相同时认为是同一个错误，部分错误码存在重复，因此需要同时比较错误信息
+func (e errCode) Is(target error) bool {
+	c, ok := target.(coder)
+	return ok && c.Code() == e.code && c.Message() == e.message
+}
+
+// Code 错误码
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Message 错误信息
+func (e *Error) Message() string {
+	return e.message
+}
+
+func (e *Error) Error() string {
+	buf := strings.Builder{}
+	buf.WriteString(e.code)
+	buf.WriteString(" - ")
+	buf.WriteString(e.message)
+	if e.SqlId != "" {
+		buf.WriteString(" [sqlId: ")
+		buf.WriteString(e.SqlId)
+		buf.WriteString("]")
+	}
+	if _, ok := e.Cause.(errCode); !ok && e.Cause != nil {
+		buf.WriteString(": ")
+		buf.WriteString(e.Cause.Error())
+	}
+	return buf.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is 错误码以及错误信息相同时认为是同一个错误
+func (e *Error) Is(target error) bool {
+	c, ok := target.(coder)
+	return ok && c.Code() == e.code && c.Message() == e.message
+}
+
+// WithStatement 为错误附加语句信息
+// err包含错误码时沿用该错误码，否则使用code的错误码；err本身是Error时返回补充了缺失语句信息的副本，不修改err
+func WithStatement(err error, code error, sqlId, sql, driver string) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		ret := *e
+		if ret.SqlId == "" {
+			ret.SqlId = sqlId
+		}
+		if ret.Sql == "" {
+			ret.Sql = sql
+		}
+		if ret.Driver == "" {
+			ret.Driver = driver
+		}
+		return &ret
+	}
+	ret := &Error{
+		SqlId:  sqlId,
+		Sql:    sql,
+		Driver: driver,
+		Cause:  err,
+	}
+	var c coder
+	if stderrors.As(err, &c) || stderrors.As(code, &c) {
+		ret.code, ret.message = c.Code(), c.Message()
+	}
+	return ret
+}
+
+// WithCode 使用code的错误码替换err的错误码，err本身是Error时保留语句信息以及原始错误，否则err作为原始错误
+func WithCode(err error, code error) error {
+	if err == nil {
+		return nil
+	}
+	var c coder
+	if !stderrors.As(code, &c) {
+		return err
+	}
+	if e, ok := err.(*Error); ok {
+		ret := *e
+		ret.code, ret.message = c.Code(), c.Message()
+		return &ret
+	}
+	return &Error{
+		code:    c.Code(),
+		message: c.Message(),
+		Cause:   err,
+	}
+}
+
+// Is 同标准库errors.Is
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As 同标准库errors.As
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
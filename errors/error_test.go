@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	cause := fmt.Errorf("driver failed")
+	err := WithStatement(cause, StatementQueryError, "test.select", "SELECT 1", "mysql")
+	if !Is(err, StatementQueryError) || !Is(err, cause) || Is(err, StatementExecError) {
+		t.Fatal("unexpected Is result ", err)
+	}
+	var e *Error
+	if !As(err, &e) || e.Code() != "24001" || e.SqlId != "test.select" || e.Sql != "SELECT 1" || e.Driver != "mysql" || e.Unwrap() != cause {
+		t.Fatalf("unexpected error %+v", e)
+	}
+	if s := err.Error(); s != "24001 - statement query error [sqlId: test.select]: driver failed" {
+		t.Fatal("unexpected message ", s)
+	}
+
+	// 包含错误码的错误沿用其错误码
+	err = WithStatement(fmt.Errorf("wrap: %w", ResultMapNotFound), StatementQueryError, "test.select", "", "")
+	if !Is(err, ResultMapNotFound) || Is(err, StatementQueryError) {
+		t.Fatal("unexpected Is result ", err)
+	}
+	err = WithStatement(ExecutorTimeout, StatementQueryError, "test.select", "", "")
+	if s := err.Error(); s != "21004 - Statement execution timeout [sqlId: test.select]" {
+		t.Fatal("unexpected message ", s)
+	}
+
+	// 已经包含语句信息时只补充缺失的信息，返回副本而不修改原错误
+	inner := WithStatement(cause, StatementQueryError, "test.nested", "", "")
+	err = WithStatement(inner, StatementExecError, "test.select", "SELECT 1", "mysql")
+	if err == inner || !As(err, &e) || e.SqlId != "test.nested" || e.Sql != "SELECT 1" || e.Driver != "mysql" {
+		t.Fatalf("unexpected error %+v", e)
+	}
+	if e := inner.(*Error); e.Sql != "" || e.Driver != "" {
+		t.Fatalf("inner error modified %+v", e)
+	}
+	if WithStatement(nil, StatementQueryError, "", "", "") != nil {
+		t.Fatal("expect nil")
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	cause := fmt.Errorf("driver failed")
+	inner := WithStatement(cause, StatementQueryError, "test.select", "SELECT 1", "mysql")
+	err := WithCode(inner, ExecutorTimeout)
+	var e *Error
+	if !Is(err, ExecutorTimeout) || !Is(err, cause) || !As(err, &e) || e.SqlId != "test.select" || e.Sql != "SELECT 1" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !Is(inner, StatementQueryError) {
+		t.Fatal("inner error modified ", inner)
+	}
+	if err := WithCode(cause, ExecutorTimeout); !Is(err, ExecutorTimeout) || !Is(err, cause) {
+		t.Fatal("unexpected error ", err)
+	}
+	if WithCode(nil, ExecutorTimeout) != nil {
+		t.Fatal("expect nil")
+	}
+}
+
+func TestCodeIs(t *testing.T) {
+	if !Is(gobatisError("24001", "statement query error"), StatementQueryError) {
+		t.Fatal("expect same code errors match")
+	}
+	if Is(ResultTooManyRows, ResultSetValueFailed) {
+		t.Fatal("expect different code errors not match")
+	}
+	// 错误码重复的错误通过错误信息区分
+	if Is(ExecutorQueryError, ExecutorGetConnectionError) || Is(WithStatement(fmt.Errorf("x"), ExecutorQueryError, "", "", ""), ExecutorGetConnectionError) {
+		t.Fatal("expect different errors with same code not match")
+	}
+	if !Is(StatementQueryError, WithStatement(fmt.Errorf("x"), StatementQueryError, "", "", "")) {
+		t.Fatal("expect sentinel match error with same code")
+	}
+}
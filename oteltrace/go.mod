@@ -0,0 +1,31 @@
+module github.com/xfali/gobatis/v2/oteltrace
+
+go 1.18
+
+require (
+	github.com/xfali/gobatis/v2 v2.0.1-0.20261016221118-41363a08588d
+	github.com/xfali/lean v0.0.0-20250126150312-49f428d3c88f
+	go.opentelemetry.io/otel v1.14.0
+	go.opentelemetry.io/otel/sdk v1.14.0
+	go.opentelemetry.io/otel/trace v1.14.0
+)
+
+require (
+	github.com/Masterminds/goutils v1.1.1 // indirect
+	github.com/Masterminds/semver/v3 v3.1.1 // indirect
+	github.com/Masterminds/sprig/v3 v3.2.2 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.1.1 // indirect
+	github.com/huandu/xstrings v1.3.1 // indirect
+	github.com/imdario/mergo v0.3.11 // indirect
+	github.com/mitchellh/copystructure v1.0.0 // indirect
+	github.com/mitchellh/reflectwalk v1.0.0 // indirect
+	github.com/shopspring/decimal v1.2.0 // indirect
+	github.com/spf13/cast v1.3.1 // indirect
+	github.com/xfali/loadbalance v0.0.1 // indirect
+	github.com/xfali/reflection v0.0.0-20230406143950-299589bbddbe // indirect
+	github.com/xfali/xlog v0.1.6 // indirect
+	golang.org/x/crypto v0.0.0-20200414173820-0848c9571904 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+)
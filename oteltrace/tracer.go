@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/xfali/gobatis/v2/runner/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer 使用OpenTelemetry实现v1.Tracer
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+type span struct {
+	span trace.Span
+}
+
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{
+		tracer: tracer,
+	}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, v1.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, &span{span: s}
+}
+
+func (s *span) SetTag(key string, value interface{}) {
+	s.span.SetAttributes(toAttribute(key, value))
+}
+
+func (s *span) Finish(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func toAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oteltrace
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/xfali/gobatis/v2/runner/v1"
+	"github.com/xfali/lean/connection"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/lean/session"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+type testFactory struct{}
+
+func (f testFactory) GetDriverName() string {
+	return "mysql"
+}
+
+func (f testFactory) CreateConnection() connection.Connection {
+	return testConnection{}
+}
+
+type testConnection struct{}
+
+func (c testConnection) Open() error {
+	return nil
+}
+
+func (c testConnection) GetSession() (session.Session, error) {
+	return testSession{Session: session.NewDummySession(0)}, nil
+}
+
+func (c testConnection) Close() error {
+	return nil
+}
+
+type testSession struct {
+	session.Session
+}
+
+func (s testSession) Execute(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	if stmt == "DELETE FROM tbl_fail" {
+		return nil, errors.New("execute failed")
+	}
+	return testResult{}, nil
+}
+
+type testResult struct {
+	resultset.Result
+}
+
+func (r testResult) RowsAffected() (int64, error) {
+	return 2, nil
+}
+
+func (r testResult) Close() error {
+	return nil
+}
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	sm := v1.NewSessionManager(testFactory{})
+	sm.SetTracer(NewTracer(provider.Tracer("gobatis")))
+	sess := sm.NewSession()
+
+	ctx := context.Background()
+	err := sess.Tx(ctx, func(session *v1.Session) error {
+		return session.Update("UPDATE tbl_user SET name = #{0}").Param("x").Result(nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Delete("DELETE FROM tbl_fail").Param().Result(nil); err == nil {
+		t.Fatal("expect error")
+	}
+
+	spans := exporter.GetSpans()
+	names := make([]string, len(spans))
+	for i := range spans {
+		names[i] = spans[i].Name
+	}
+	expect := []string{v1.SpanBegin, v1.SpanStatement, v1.SpanCommit, v1.SpanStatement}
+	if len(names) != len(expect) {
+		t.Fatal("expect ", expect, " but get ", names)
+	}
+	for i := range expect {
+		if names[i] != expect[i] {
+			t.Fatal("expect ", expect, " but get ", names)
+		}
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[1].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs[v1.TagStatement].AsString() != "UPDATE tbl_user SET name = ?" {
+		t.Fatal("expect rendered sql but get ", attrs[v1.TagStatement].AsString())
+	}
+	if attrs[v1.TagRowsAffected].AsInt64() != 2 || attrs[v1.TagAction].AsString() != "update" {
+		t.Fatal("expect rows affected and action but get ", attrs)
+	}
+	if spans[3].Status.Code != codes.Error || len(spans[3].Events) == 0 {
+		t.Fatal("expect error span but get ", spans[3].Status)
+	}
+}
@@ -20,6 +20,7 @@ package parsing
 import (
 	"github.com/xfali/gobatis/v2/parsing/parser"
 	"github.com/xfali/gobatis/v2/reflection"
+	"github.com/xfali/gobatis/v2/typehandler"
 	reflection2 "github.com/xfali/reflection"
 	"github.com/xfali/xlog"
 	"strings"
@@ -37,6 +38,7 @@ type DynamicElement interface {
 type DynamicData struct {
 	OriginData     string
 	DynamicElemMap map[string]DynamicElement
+	Attributes     parser.Attributes
 }
 
 func (dynamicData *DynamicData) Replace(params ...interface{}) string {
@@ -53,6 +55,7 @@ func (dynamicData *DynamicData) ReplaceWithMap(objParams map[string]interface{})
 
 	getFunc := func(s string) string {
 		if o, ok := objParams[s]; ok {
+			o = typehandler.Unwrap(o)
 			if str, ok := o.(string); ok {
 				return str
 			}
@@ -83,5 +86,10 @@ func (dynamicData *DynamicData) ReplaceWithMap(objParams map[string]interface{})
 func (dynamicData *DynamicData) ParseMetadata(driverName string, params ...interface{}) (*parser.Metadata, error) {
 	paramMap := reflection.ParseParams(params...)
 	sqlStr := dynamicData.ReplaceWithMap(paramMap)
-	return sqlparser.ParseWithParamMap(driverName, sqlStr, paramMap)
+	md, err := sqlparser.ParseWithParamMap(driverName, sqlStr, paramMap)
+	if err != nil {
+		return nil, err
+	}
+	md.Attributes = dynamicData.Attributes
+	return md, nil
 }
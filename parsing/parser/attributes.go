@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import "time"
+
+// Attributes 语句定义时的附加属性，如xml mapper中select元素的fetchSize
+// 由解析器在ParseMetadata时带入Metadata，供runner执行时使用
+type Attributes struct {
+	// FetchSize 游标读取时的行数提示，0表示未设置
+	FetchSize int
+	// Timeout 语句执行超时时间，0表示未设置
+	Timeout time.Duration
+	// UseGeneratedKeys insert语句是否将数据库生成的主键写回参数
+	UseGeneratedKeys bool
+	// KeyProperty 接收生成主键的参数字段，对应column tag或者字段名
+	KeyProperty []string
+	// KeyColumn 生成主键的列名，为空时与KeyProperty相同
+	KeyColumn []string
+	// Namespace 语句所属的namespace
+	Namespace string
+	// Cache namespace的二级缓存配置，nil表示未开启
+	Cache *CacheConfig
+	// UseCache select语句是否使用二级缓存
+	UseCache bool
+	// FlushCache 执行后是否清空namespace的二级缓存
+	FlushCache bool
+	// ResultMap select语句使用的resultMap完整id，为空时按照列名映射
+	ResultMap string
+	// StatementType 语句类型：STATEMENT、PREPARED、CALLABLE，为空表示未设置
+	StatementType string
+}
+
+// CacheConfig namespace二级缓存配置，对应xml mapper中的cache元素
+type CacheConfig struct {
+	// Size 最大缓存条目数，0表示使用默认值
+	Size int
+	// TTL 缓存过期时间，0表示不过期
+	TTL time.Duration
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+// 语句类型，对应xml语句的statementType属性
+const (
+	StatementTypeStatement = "STATEMENT"
+	StatementTypePrepared  = "PREPARED"
+	StatementTypeCallable  = "CALLABLE"
+)
+
+// 存储过程参数模式，使用#{name,mode=OUT}指定，默认为IN
+const (
+	ParamModeIn    = "IN"
+	ParamModeOut   = "OUT"
+	ParamModeInOut = "INOUT"
+)
+
+// OutParam 存储过程的OUT以及INOUT参数，执行后写回参数
+type OutParam struct {
+	// Index 参数在Metadata.Params中的序号
+	Index int
+	// Name 参数名，如user.Total或者0
+	Name string
+	// Mode OUT或者INOUT
+	Mode string
+}
@@ -17,47 +17,52 @@
 package parser
 
 import (
+	"github.com/xfali/gobatis/v2/dialect"
 	"strconv"
 	"strings"
 )
 
 type Holder func(int) string
 
+// ParamPlaceHolder 参数占位符
+// Deprecated: 使用dialect.Dialect的Placeholder
 type ParamPlaceHolder interface {
 	GetByIndex(index int) string
 	GetByName(name string) string
 	Replace(s, old string, index int, name string) string
 }
 
-var gHolderMap = map[string]Holder{
-	"mysql":    MysqlHolder,    //mysql
-	"postgres": PostgresHolder, //postgresql
-	"oci8":     Oci8Holder,     //oracle
-	"adodb":    MysqlHolder,    //sqlserver
-}
+var (
+	_ ParamPlaceHolder = (*MysqlParamPlaceHolder)(nil)
+	_ ParamPlaceHolder = (*PostgresParamPlaceHolder)(nil)
+	_ ParamPlaceHolder = (*Oci8ParamPlaceHolder)(nil)
+)
 
+// RegisterParamHolder 修改驱动使用的占位符，驱动方言的其他语法保持不变
+// Deprecated: 使用dialect.Register注册完整的方言
 func RegisterParamHolder(driverName string, h Holder) bool {
-	_, ok := GetHolder(driverName)
-	gHolderMap[driverName] = h
-	return ok
+	return dialect.Register(driverName, dialect.WithPlaceholder(dialect.Select(driverName), h))
 }
 
+// SelectHolder 获得驱动使用的占位符，未注册时使用?
 func SelectHolder(driverName string) Holder {
-	if v, ok := GetHolder(driverName); ok {
-		return v
-	}
-	return MysqlHolder
+	return dialect.Select(driverName).Placeholder
 }
 
 func GetHolder(driverName string) (Holder, bool) {
-	v, ok := gHolderMap[driverName]
-	return v, ok
+	d, ok := dialect.Get(driverName)
+	if !ok {
+		return nil, false
+	}
+	return d.Placeholder, true
 }
 
 func MysqlHolder(int) string {
 	return "?"
 }
 
+// MysqlParamPlaceHolder mysql的?占位符
+// Deprecated: 使用dialect.Select("mysql").Placeholder
 type MysqlParamPlaceHolder struct {
 }
 
@@ -77,6 +82,8 @@ func PostgresHolder(i int) string {
 	return "$" + strconv.Itoa(i)
 }
 
+// PostgresParamPlaceHolder postgresql的$n占位符
+// Deprecated: 使用dialect.Select("postgres").Placeholder
 type PostgresParamPlaceHolder struct {
 }
 
@@ -88,14 +95,16 @@ func (h *PostgresParamPlaceHolder) GetByName(name string) string {
 	return "?"
 }
 
-func (h *PostgresParamPlaceHolder) Replace(s, old string, index int, name string) (string, bool) {
-	return strings.Replace(s, old, h.GetByIndex(index), 1), true
+func (h *PostgresParamPlaceHolder) Replace(s, old string, index int, name string) string {
+	return strings.Replace(s, old, h.GetByIndex(index), 1)
 }
 
 func Oci8Holder(i int) string {
 	return ":" + strconv.Itoa(i)
 }
 
+// Oci8ParamPlaceHolder oracle的:n占位符
+// Deprecated: 使用dialect.Select("oci8").Placeholder
 type Oci8ParamPlaceHolder struct {
 }
 
@@ -27,6 +27,9 @@ type Metadata struct {
 	PrepareSql string
 	Vars       []string
 	Params     []interface{}
+	// Outs 存储过程的OUT以及INOUT参数
+	Outs       []OutParam
+	Attributes Attributes
 }
 
 func (md *Metadata) String() string {
@@ -50,12 +53,18 @@ type Registry interface {
 }
 
 type simpleRegistry struct {
-	parserMap map[string]Parser
+	parserMap    map[string]Parser
+	resultMaps   map[string]*ResultMap
+	constructors map[string]interface{}
+	resultTypes  map[string]func() interface{}
 }
 
 func NewSimpleRegistry() *simpleRegistry {
 	return &simpleRegistry{
-		parserMap: map[string]Parser{},
+		parserMap:    map[string]Parser{},
+		resultMaps:   map[string]*ResultMap{},
+		constructors: map[string]interface{}{},
+		resultTypes:  map[string]func() interface{}{},
 	}
 }
 
@@ -98,6 +107,33 @@ func (r *simpleRegistry) FindParser(sqlId string) (Parser, bool) {
 	return v, ok
 }
 
+func (r *simpleRegistry) AddResultMap(rm *ResultMap) {
+	r.resultMaps[rm.Id] = rm
+}
+
+func (r *simpleRegistry) FindResultMap(id string) (*ResultMap, bool) {
+	v, ok := r.resultMaps[id]
+	return v, ok
+}
+
+func (r *simpleRegistry) AddConstructor(name string, fn interface{}) {
+	r.constructors[name] = fn
+}
+
+func (r *simpleRegistry) FindConstructor(name string) (interface{}, bool) {
+	v, ok := r.constructors[name]
+	return v, ok
+}
+
+func (r *simpleRegistry) AddResultType(name string, factory func() interface{}) {
+	r.resultTypes[name] = factory
+}
+
+func (r *simpleRegistry) FindResultType(name string) (func() interface{}, bool) {
+	v, ok := r.resultTypes[name]
+	return v, ok
+}
+
 type defaultParserRegistry struct {
 	rr   *simpleRegistry
 	lock sync.RWMutex
@@ -143,3 +179,45 @@ func (r *defaultParserRegistry) FindParser(sqlId string) (Parser, bool) {
 
 	return r.rr.FindParser(sqlId)
 }
+
+func (r *defaultParserRegistry) AddResultMap(rm *ResultMap) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rr.AddResultMap(rm)
+}
+
+func (r *defaultParserRegistry) FindResultMap(id string) (*ResultMap, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.rr.FindResultMap(id)
+}
+
+func (r *defaultParserRegistry) AddConstructor(name string, fn interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rr.AddConstructor(name, fn)
+}
+
+func (r *defaultParserRegistry) FindConstructor(name string) (interface{}, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.rr.FindConstructor(name)
+}
+
+func (r *defaultParserRegistry) AddResultType(name string, factory func() interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rr.AddResultType(name, factory)
+}
+
+func (r *defaultParserRegistry) FindResultType(name string) (func() interface{}, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.rr.FindResultType(name)
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+// ResultMap 结果映射，对应xml mapper中的resultMap元素
+type ResultMap struct {
+	// Id resultMap的完整id：namespace.id
+	Id string
+	// Type 结果类型名称，仅用于说明
+	Type string
+	// AutoMapping 未配置映射的列是否按照列名自动映射
+	AutoMapping bool
+	// Results 列与属性的映射
+	Results []ResultMapping
+	// Associations 一对一的嵌套映射
+	Associations []NestedMapping
+	// Collections 一对多的嵌套映射，按照id列将多行结果合并到同一个对象
+	Collections []NestedMapping
+	// Discriminator 根据列值选择映射，nil表示未设置
+	Discriminator *Discriminator
+	// Constructor 使用注册的构造函数创建结果对象，nil表示直接创建结构体
+	Constructor *Constructor
+}
+
+// Constructor 构造函数映射，列值按照顺序转换为构造函数的参数
+type Constructor struct {
+	// Name 注册的构造函数名称
+	Name string
+	Args []ConstructorArg
+}
+
+// ConstructorArg 构造函数的一个参数
+type ConstructorArg struct {
+	// Column 列名，忽略大小写
+	Column string
+	// Id 是否为id列
+	Id bool
+	// TypeHandler 转换该列使用的命名处理器，为空时按照参数类型查找
+	TypeHandler string
+	// JdbcType 列的jdbcType，用于查找按照jdbcType注册的处理器
+	JdbcType string
+}
+
+// NestedMapping association或者collection映射
+type NestedMapping struct {
+	// Property 属性名，association为结构体或结构体指针，collection为slice
+	Property string
+	// ColumnPrefix 嵌套映射中列名的前缀，会与外层的前缀拼接
+	ColumnPrefix string
+	// ResultMap 内联定义的映射
+	ResultMap *ResultMap
+	// ResultMapId 引用的resultMap完整id，ResultMap为nil时使用
+	ResultMapId string
+	// Select 嵌套select语句的完整id，不为空时使用Column列的值作为参数执行该语句获得属性值
+	Select string
+	// Column 传给嵌套select的列，单个列时参数为列值，多个列使用{name=column,...}格式，参数为以name为key的map
+	Column string
+	// Lazy 是否在第一次访问时才执行嵌套select，属性类型必须为Lazy[T]
+	Lazy bool
+}
+
+// Discriminator 鉴别器，根据列值选择case中的映射以及结果类型
+type Discriminator struct {
+	// Column 鉴别列名，忽略大小写
+	Column string
+	// TypeHandler 将列值转换为string使用的命名处理器，为空时按照string类型查找
+	TypeHandler string
+	// JdbcType 列的jdbcType，用于查找按照jdbcType注册的处理器
+	JdbcType string
+	Cases    []DiscriminatorCase
+}
+
+// DiscriminatorCase 鉴别器的一个分支，映射时先应用外层resultMap的映射，再应用case的映射
+type DiscriminatorCase struct {
+	// Value 匹配的列值
+	Value string
+	// ResultType 结果类型名称，为空时使用case映射的type，再为空时使用外层resultMap的type
+	ResultType string
+	// ResultMap 内联定义的映射
+	ResultMap *ResultMap
+	// ResultMapId 引用的resultMap完整id，ResultMap为nil时使用
+	ResultMapId string
+}
+
+// HasNested 是否包含association或者collection
+func (rm *ResultMap) HasNested() bool {
+	return len(rm.Associations) > 0 || len(rm.Collections) > 0
+}
+
+// ResultMapping 一个列与属性的映射
+type ResultMapping struct {
+	// Property 属性名，匹配字段名（忽略大小写）或者column tag，使用.分隔嵌套属性
+	Property string
+	// Column 列名，忽略大小写
+	Column string
+	// Id 是否为id列
+	Id bool
+	// TypeHandler 解析该列使用的命名处理器，为空时使用字段tag或者按照字段类型查找
+	TypeHandler string
+	// JdbcType 列的jdbcType，用于查找按照jdbcType注册的处理器
+	JdbcType string
+}
+
+// ResultMapRegistry 保存resultMap以及映射时使用的构造函数、结果类型，与语句保存在同一个Registry中
+// NewRegistry以及NewSimpleRegistry创建的Registry均实现了该接口
+type ResultMapRegistry interface {
+	// AddResultMap 注册resultMap，id相同时覆盖
+	AddResultMap(rm *ResultMap)
+	// FindResultMap 根据完整id查找resultMap
+	FindResultMap(id string) (*ResultMap, bool)
+	// AddConstructor 注册constructor引用的构造函数，名称相同时覆盖
+	AddConstructor(name string, fn interface{})
+	// FindConstructor 根据名称查找构造函数
+	FindConstructor(name string) (interface{}, bool)
+	// AddResultType 注册type名称对应的结果对象工厂，名称相同时覆盖
+	AddResultType(name string, factory func() interface{})
+	// FindResultType 根据type名称查找结果对象工厂
+	FindResultType(name string) (func() interface{}, bool)
+}
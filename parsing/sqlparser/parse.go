@@ -19,13 +19,24 @@ package sqlparser
 
 import (
 	"fmt"
+	"github.com/xfali/gobatis/v2/dialect"
 	"github.com/xfali/gobatis/v2/errors"
 	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
 	"strconv"
 	"strings"
 	"unicode"
 )
 
+const (
+	// OptionTypeHandler #{}参数选项，指定参数使用的命名处理器
+	OptionTypeHandler = "typeHandler"
+	// OptionJdbcType #{}参数选项，指定参数的jdbcType
+	OptionJdbcType = "jdbcType"
+	// OptionMode #{}参数选项，存储过程参数模式：IN、OUT、INOUT
+	OptionMode = "mode"
+)
+
 const (
 	SELECT = "select"
 	INSERT = "insert"
@@ -104,8 +115,12 @@ func ParseWithParams(sql string, params ...interface{}) (*parser.Metadata, error
 						if len(params) <= indexV {
 							return nil, errors.ParseSqlParamError
 						}
+						value, err := typehandler.Bind(params[indexV], "", "")
+						if err != nil {
+							return nil, err
+						}
 						oldStr := "${" + varName + "}"
-						newStr := interface2String(params[indexV])
+						newStr := interface2String(value)
 						ret.PrepareSql = strings.Replace(ret.PrepareSql, oldStr, newStr, -1)
 						subStr = strings.Replace(subStr, oldStr, newStr, -1)
 					} else if c == "#" {
@@ -114,7 +129,11 @@ func ParseWithParams(sql string, params ...interface{}) (*parser.Metadata, error
 						}
 						oldStr := "#{" + varName + "}"
 						ret.PrepareSql = strings.Replace(ret.PrepareSql, oldStr, "?", -1)
-						ret.Params = append(ret.Params, params[indexV])
+						value, err := typehandler.Bind(params[indexV], "", "")
+						if err != nil {
+							return nil, err
+						}
+						ret.Params = append(ret.Params, value)
 					}
 				}
 			}
@@ -137,7 +156,7 @@ func ParseWithParamMap(driverName, sql string, params map[string]interface{}) (*
 	firstIndex, lastIndex := -1, -1
 	var c string
 	var index int = 0
-	holder := parser.SelectHolder(driverName)
+	holder := dialect.Select(driverName).Placeholder
 
 	for {
 		firstIndex = strings.Index(subStr, "{")
@@ -146,26 +165,38 @@ func ParseWithParamMap(driverName, sql string, params map[string]interface{}) (*
 		} else {
 			c = subStr[firstIndex-1 : firstIndex]
 			subStr = subStr[firstIndex+1:]
-			lastIndex = findFirst(subStr, '}')
+			if c == "#" {
+				lastIndex = findParamEnd(subStr)
+			} else {
+				lastIndex = findFirst(subStr, '}')
+			}
 			//lastIndex = strings.Index(subStr, "}")
 			if lastIndex == -1 {
 				return nil, errors.ParseSqlVarError
 			} else {
-				varName := subStr[:lastIndex]
+				varExpr := subStr[:lastIndex]
+				varName, opts := parseParamOptions(varExpr)
 				if varName != "" {
 					ret.Vars = append(ret.Vars, varName)
 					if value, ok := params[varName]; ok {
+						value, err := typehandler.Bind(value, opts[OptionTypeHandler], opts[OptionJdbcType])
+						if err != nil {
+							return nil, err
+						}
 						if c == "$" {
-							oldStr := "${" + varName + "}"
+							oldStr := "${" + varExpr + "}"
 							newStr := interface2String(value)
 							ret.PrepareSql = strings.Replace(ret.PrepareSql, oldStr, newStr, -1)
 							subStr = strings.Replace(subStr, oldStr, newStr, -1)
 						} else if c == "#" {
-							oldStr := "#{" + varName + "}"
+							oldStr := "#{" + varExpr + "}"
 							index++
 							h := holder(index)
 							ret.PrepareSql = strings.Replace(ret.PrepareSql, oldStr, h, 1)
 							ret.Params = append(ret.Params, value)
+							if mode := strings.ToUpper(opts[OptionMode]); mode == parser.ParamModeOut || mode == parser.ParamModeInOut {
+								ret.Outs = append(ret.Outs, parser.OutParam{Index: len(ret.Params) - 1, Name: varName, Mode: mode})
+							}
 						}
 					} else {
 						return nil, errors.ParseSqlParamError
@@ -196,6 +227,36 @@ func findFirst(subStr string, char rune) int {
 	return -1
 }
 
+// findParamEnd 查找#{}参数的结束位置，参数中可以包含逗号分隔的选项
+func findParamEnd(subStr string) int {
+	for i, r := range subStr {
+		switch r {
+		case '}':
+			return i
+		case '{', '\n', '\r':
+			return -1
+		}
+	}
+	return -1
+}
+
+// parseParamOptions 解析参数名以及选项，如：name,typeHandler=json,jdbcType=VARCHAR
+func parseParamOptions(expr string) (string, map[string]string) {
+	items := strings.Split(expr, ",")
+	name := strings.TrimSpace(items[0])
+	if len(items) == 1 {
+		return name, nil
+	}
+	opts := make(map[string]string, len(items)-1)
+	for _, item := range items[1:] {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 2 {
+			opts[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return name, opts
+}
+
 func interface2String(i interface{}) string {
 	return fmt.Sprintf("%v", i)
 }
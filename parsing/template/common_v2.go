@@ -93,12 +93,17 @@ func (d *CommonV2Dynamic) getParam() []interface{} {
 	return nil
 }
 
-func (d *CommonV2Dynamic) Param(p interface{}) string {
+// Param 生成参数占位符，handler可以指定参数使用的命名处理器，如{{arg .Tags "csv"}}
+func (d *CommonV2Dynamic) Param(p interface{}, handler ...string) (string, error) {
+	v, err := bindParam(p, handler)
+	if err != nil {
+		return "", err
+	}
 	d.index++
 	key := getPlaceHolderKey(d.index)
-	d.paramMap[key] = p
+	d.paramMap[key] = v
 	d.keys = append(d.keys, key)
-	return key
+	return key, nil
 }
 
 func (d *CommonV2Dynamic) format(s string) (string, []interface{}) {
@@ -19,7 +19,9 @@ package template
 
 import (
 	"fmt"
+	"github.com/xfali/gobatis/v2/dialect"
 	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
 	"strings"
 	"text/template"
 	"time"
@@ -52,10 +54,22 @@ func dummyWhere(b interface{}, cond, column string, value interface{}, origin st
 }
 
 //return as fast as possible
-func dummyParam(p interface{}) string {
+func dummyParam(p interface{}, handler ...string) string {
 	return ""
 }
 
+// bindParam 使用参数指定的、字段typeHandler tag指定的或者按照类型注册的处理器转换参数
+func bindParam(p interface{}, handler []string) (interface{}, error) {
+	name := ""
+	for _, h := range handler {
+		if h != "" {
+			name = h
+			break
+		}
+	}
+	return typehandler.Bind(p, name, "")
+}
+
 func dummyNil(p interface{}) bool {
 	return true
 }
@@ -72,6 +86,8 @@ var dummyFuncMap = template.FuncMap{
 	FuncNameArg:   dummyParam,
 
 	FuncNameAdd: commonAdd,
+
+	funcNameFieldTag: fieldTag,
 }
 
 var gDummyDynamic = &DummyDynamic{}
@@ -179,12 +195,17 @@ func (dynamic *CommonDynamic) getParam() []interface{} {
 	return nil
 }
 
-func (dynamic *CommonDynamic) Param(p interface{}) string {
+// Param 生成参数占位符，handler可以指定参数使用的命名处理器，如{{arg .Tags "csv"}}
+func (dynamic *CommonDynamic) Param(p interface{}, handler ...string) (string, error) {
+	v, err := bindParam(p, handler)
+	if err != nil {
+		return "", err
+	}
 	dynamic.index++
 	key := getPlaceHolderKey(dynamic.index)
-	dynamic.paramMap[key] = p
+	dynamic.paramMap[key] = v
 	dynamic.keys = append(dynamic.keys, key)
-	return key
+	return key, nil
 }
 
 func (dynamic *CommonDynamic) format(s string) (string, []interface{}) {
@@ -201,8 +222,8 @@ func (dynamic *CommonDynamic) format(s string) (string, []interface{}) {
 }
 
 func selectDynamic(driverName string) Dynamic {
-	if h, ok := parser.GetHolder(driverName); ok {
-		return dynamicFac(h)
+	if d, ok := dialect.Get(driverName); ok {
+		return dynamicFac(d.Placeholder)
 	}
 	return gDummyDynamic
 }
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package template
+
+import (
+	"github.com/xfali/gobatis/v2/typehandler"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// funcNameFieldTag 获得字段typeHandler tag的内部函数，由bindFieldTags插入模板
+const funcNameFieldTag = "_xfali_field_tag"
+
+// fieldTag 沿着字段路径查找dot中对应字段的typeHandler tag，无法通过类型确定字段时返回空
+func fieldTag(dot interface{}, path string) string {
+	t := reflect.TypeOf(dot)
+	tag := ""
+	for _, name := range strings.Split(path, ".") {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return ""
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return ""
+		}
+		t, tag = f.Type, f.Tag.Get(typehandler.TagName)
+	}
+	return tag
+}
+
+// bindFieldTags 将没有指定处理器的{{arg .Field}}改写为{{arg .Field (fieldTag . "Field")}}
+// 使模板与xml一样使用字段typeHandler tag指定的处理器，只在解析模板时执行一次
+func bindFieldTags(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Nodes {
+			bindFieldTags(v)
+		}
+	case *parse.ActionNode:
+		bindFieldTags(n.Pipe)
+	case *parse.IfNode:
+		bindBranchFieldTags(&n.BranchNode)
+	case *parse.RangeNode:
+		bindBranchFieldTags(&n.BranchNode)
+	case *parse.WithNode:
+		bindBranchFieldTags(&n.BranchNode)
+	case *parse.TemplateNode:
+		bindFieldTags(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			bindFieldTags(cmd)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			bindFieldTags(arg)
+		}
+		if len(n.Args) != 2 {
+			return
+		}
+		ident, ok := n.Args[0].(*parse.IdentifierNode)
+		if !ok || ident.Ident != FuncNameArg {
+			return
+		}
+		field, ok := n.Args[1].(*parse.FieldNode)
+		if !ok {
+			return
+		}
+		path := strings.Join(field.Ident, ".")
+		n.Args = append(n.Args, &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      field.Pos,
+			Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Pos:      field.Pos,
+				Args: []parse.Node{
+					parse.NewIdentifier(funcNameFieldTag).SetPos(field.Pos),
+					&parse.DotNode{NodeType: parse.NodeDot, Pos: field.Pos},
+					&parse.StringNode{NodeType: parse.NodeString, Pos: field.Pos, Quoted: strconv.Quote(path), Text: path},
+				},
+			}},
+		})
+	}
+}
+
+func bindBranchFieldTags(n *parse.BranchNode) {
+	bindFieldTags(n.Pipe)
+	bindFieldTags(n.List)
+	bindFieldTags(n.ElseList)
+}
+
+func bindTemplatesFieldTags(tpl *template.Template) {
+	for _, t := range tpl.Templates() {
+		if t.Tree != nil {
+			bindFieldTags(t.Tree.Root)
+		}
+	}
+}
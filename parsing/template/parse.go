@@ -21,6 +21,7 @@ import (
 	"github.com/xfali/gobatis/v2/parsing/parser"
 	"github.com/xfali/xlog"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -43,6 +44,7 @@ func CreateParser(data []byte) (*Parser, error) {
 	if err != nil {
 		return nil, err
 	}
+	bindTemplatesFieldTags(tpl)
 	return &Parser{tpl: tpl}, nil
 }
 
@@ -125,57 +127,59 @@ func (manager *Manager) CreateDynamicStatementParser(sql string) (parser.Parser,
 
 func (manager *Manager) RegisterData(data []byte) error {
 	return manager.registry.Direct(func(r parser.Registry) error {
-		tpl := template.New("")
-		tpl = tpl.Funcs(dummyFuncMap)
-		tpl, err := tpl.Parse(string(data))
+		ns, tpls, err := parseTemplates(data)
 		if err != nil {
 			manager.logger.Warnf("register template data failed: %s err: %v\n", string(data), err)
 			return err
 		}
 
-		ns := getNamespace(tpl)
-		tpls := tpl.Templates()
-		for _, v := range tpls {
-			if v.Name() != "" && v.Name() != namespaceTmplName {
-				addErr := r.AddParser(ns+v.Name(), &Parser{tpl: v})
-				if addErr != nil {
-					return addErr
-				}
-			}
-		}
-
-		return nil
+		return addParsers(r, ns, tpls)
 	})
 }
 
 func (manager *Manager) RegisterFile(file string) error {
 	return manager.registry.Direct(func(r parser.Registry) error {
-		tpl := template.New("")
 		data, err := ioutil.ReadFile(file)
 		if err != nil {
 			manager.logger.Warnf("register template file failed: %s err: %v\n", file, err)
 			return err
 		}
-		tpl = tpl.Funcs(dummyFuncMap)
-		tpl, err = tpl.Parse(string(data))
+		ns, tpls, err := parseTemplates(data)
 		if err != nil {
 			manager.logger.Warnf("register template file failed: %s err: %v\n", file, err)
 			return err
 		}
 
-		ns := getNamespace(tpl)
-		tpls := tpl.Templates()
-		for _, v := range tpls {
-			if v.Name() != "" && v.Name() != namespaceTmplName {
-				addErr := r.AddParser(ns+v.Name(), &Parser{tpl: v})
-				if addErr != nil {
-					return addErr
-				}
-			}
+		return addParsers(r, ns, tpls)
+	})
+}
+
+// parseTemplates 解析模板数据，返回带"."后缀的namespace以及其中定义的语句模板
+func parseTemplates(data []byte) (string, []*template.Template, error) {
+	tpl := template.New("")
+	tpl = tpl.Funcs(dummyFuncMap)
+	tpl, err := tpl.Parse(string(data))
+	if err != nil {
+		return "", nil, err
+	}
+	bindTemplatesFieldTags(tpl)
+
+	var ret []*template.Template
+	for _, v := range tpl.Templates() {
+		if v.Name() != "" && v.Name() != namespaceTmplName {
+			ret = append(ret, v)
 		}
+	}
+	return getNamespace(tpl), ret, nil
+}
 
-		return nil
-	})
+func addParsers(r parser.Registry, ns string, tpls []*template.Template) error {
+	for _, v := range tpls {
+		if err := r.AddParser(ns+v.Name(), &Parser{tpl: v}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func getNamespace(tpl *template.Template) string {
@@ -205,3 +209,43 @@ func (manager *Manager) FindSqlParser(sqlId string) (*Parser, bool) {
 	}
 	return nil, false
 }
+
+// Statement 模板中定义的语句
+type Statement struct {
+	Id string
+	// Action 语句类型：select、insert、update、delete，无法判断时为空
+	Action string
+}
+
+// ParseStatements 解析模板数据，返回namespace以及其中定义的语句，不注册parser
+func ParseStatements(data []byte) (string, []Statement, error) {
+	ns, tpls, err := parseTemplates(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ret := make([]Statement, 0, len(tpls))
+	for _, v := range tpls {
+		ret = append(ret, Statement{Id: v.Name(), Action: templateAction(v)})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Id < ret[j].Id
+	})
+	return strings.TrimSuffix(ns, "."), ret, nil
+}
+
+func templateAction(tpl *template.Template) string {
+	if tpl.Tree == nil || tpl.Tree.Root == nil {
+		return ""
+	}
+	fields := strings.Fields(tpl.Tree.Root.String())
+	if len(fields) == 0 {
+		return ""
+	}
+	action := strings.ToLower(fields[0])
+	switch action {
+	case "select", "insert", "update", "delete":
+		return action
+	}
+	return ""
+}
@@ -89,6 +89,15 @@ type Delete struct {
 	Data string `xml:",innerxml"`
 }
 
+type Cache struct {
+	Size          string `xml:"size,attr"`
+	FlushInterval string `xml:"flushInterval,attr"`
+	// Eviction 不支持，缓存固定使用LRU淘汰，只用于解析时给出警告
+	Eviction string `xml:"eviction,attr"`
+	// ReadOnly 不支持，只用于解析时给出警告
+	ReadOnly string `xml:"readOnly,attr"`
+}
+
 func (a *Select) ParseDynamic() {
 
 }
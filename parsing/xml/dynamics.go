@@ -50,7 +50,7 @@ type Property struct {
 type Include struct {
 	Refid      string     `xml:"refid,attr"`
 	Properties []Property `xml:"property"`
-	Sql        Sql        `xml:-`
+	Sql        Sql        `xml:"-"`
 }
 
 type If struct {
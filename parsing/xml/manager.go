@@ -18,6 +18,7 @@
 package xml
 
 import (
+	"github.com/xfali/gobatis/v2/errors"
 	"github.com/xfali/gobatis/v2/parsing"
 	"github.com/xfali/gobatis/v2/parsing/parser"
 	"github.com/xfali/xlog"
@@ -85,6 +86,15 @@ func (manager *Manager) RegisterFile(file string) error {
 }
 
 func (manager *Manager) formatMapper(registry parser.Registry, mapper *Mapper) error {
+	if rms := mapper.ResultMapDefinitions(); len(rms) > 0 {
+		rr, ok := registry.(parser.ResultMapRegistry)
+		if !ok {
+			return errors.ResultMapRegistryNotSupport
+		}
+		for _, rm := range rms {
+			rr.AddResultMap(rm)
+		}
+	}
 	ret := mapper.Format()
 	for k, v := range ret {
 		err := registry.AddParser(k, v)
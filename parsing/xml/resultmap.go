@@ -17,21 +17,33 @@
 
 package xml
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"strings"
+)
 
+// IdArg constructor中的idArg以及arg元素
 type IdArg struct {
-	Column string `xml:"column,attr"`
-	GoType string `xml:"type,attr"`
+	XMLName     xml.Name
+	Column      string `xml:"column,attr"`
+	GoType      string `xml:"type,attr"`
+	JdbcType    string `xml:"jdbcType,attr"`
+	TypeHandler string `xml:"typeHandler,attr"`
 }
 
 type Constructor struct {
-	IdArg IdArg  `xml:"idArg"`
-	Arg   string `xml:"arg"`
+	//注册的构造函数名称
+	Name string `xml:"name,attr"`
+	//idArg以及arg元素，按照定义的顺序作为构造函数的参数
+	Args []IdArg `xml:",any"`
 }
 
 type Result struct {
-	Property string `xml:"property,attr"`
-	Column   string `xml:"column,attr"`
+	Property    string `xml:"property,attr"`
+	Column      string `xml:"column,attr"`
+	JdbcType    string `xml:"jdbcType,attr"`
+	TypeHandler string `xml:"typeHandler,attr"`
 }
 
 type ResultMap struct {
@@ -40,14 +52,190 @@ type ResultMap struct {
 	Id string `xml:"id,attr"`
 	//struct类型名称
 	TypeName string `xml:"type,attr"`
+	//未配置映射的列是否按照列名自动映射，默认为true
+	AutoMapping string `xml:"autoMapping,attr"`
 	//constructor - 用于在实例化类时，注入结果到构造方法中
-	Constructor Constructor `xml:"constructor"`
+	Constructor *Constructor `xml:"constructor"`
 	//一个 ID 结果；标记出作为 ID 的结果可以帮助提高整体性能
 	ResultId Result `xml:"id"`
 	//注入到字段或 Struct 属性的普通结果
 	Results []Result `xml:"result"`
-	//TODO:
 	//association: 一个复杂类型的关联；许多结果将包装成这种类型
+	Associations []NestedResult `xml:"association"`
 	//collection: 一个复杂类型的集合
+	Collections []NestedResult `xml:"collection"`
 	//discriminator: 使用结果值来决定使用哪个 resultMap
+	Discriminator *Discriminator `xml:"discriminator"`
+}
+
+type Discriminator struct {
+	Column      string `xml:"column,attr"`
+	JdbcType    string `xml:"jdbcType,attr"`
+	TypeHandler string `xml:"typeHandler,attr"`
+	Cases       []Case `xml:"case"`
+}
+
+type Case struct {
+	Value string `xml:"value,attr"`
+	//引用其他resultMap，为空时使用内联定义的映射
+	ResultMap  string `xml:"resultMap,attr"`
+	ResultType string `xml:"resultType,attr"`
+
+	ResultId Result   `xml:"id"`
+	Results  []Result `xml:"result"`
+}
+
+// NestedResult association以及collection元素
+type NestedResult struct {
+	Property string `xml:"property,attr"`
+	//association的类型名称
+	TypeName string `xml:"javaType,attr"`
+	//collection元素的类型名称
+	OfType string `xml:"ofType,attr"`
+	//引用其他resultMap，为空时使用内联定义的映射
+	ResultMap    string `xml:"resultMap,attr"`
+	ColumnPrefix string `xml:"columnPrefix,attr"`
+	//嵌套select语句的id，使用column列的值作为参数
+	Select string `xml:"select,attr"`
+	Column string `xml:"column,attr"`
+	//lazy或者eager，默认为eager
+	FetchType string `xml:"fetchType,attr"`
+
+	ResultId     Result         `xml:"id"`
+	Results      []Result       `xml:"result"`
+	Associations []NestedResult `xml:"association"`
+	Collections  []NestedResult `xml:"collection"`
+}
+
+// definition 转换为runner使用的resultMap定义
+func (rm *ResultMap) definition(namespace string) *parser.ResultMap {
+	id := qualifiedId(namespace, rm.Id)
+	ret := &parser.ResultMap{
+		Id:          id,
+		Type:        rm.TypeName,
+		AutoMapping: parseBoolAttr(id, "autoMapping", rm.AutoMapping, true),
+	}
+	if rm.ResultId.Property != "" {
+		ret.Results = append(ret.Results, rm.ResultId.mapping(true))
+	}
+	for _, r := range rm.Results {
+		ret.Results = append(ret.Results, r.mapping(false))
+	}
+	ret.Associations = nestedMappings(namespace, id, rm.Associations)
+	ret.Collections = nestedMappings(namespace, id, rm.Collections)
+	if rm.Discriminator != nil {
+		ret.Discriminator = rm.Discriminator.definition(namespace, id)
+	}
+	if rm.Constructor != nil {
+		ret.Constructor = rm.Constructor.definition()
+	}
+	return ret
+}
+
+func (c *Constructor) definition() *parser.Constructor {
+	ret := &parser.Constructor{
+		Name: strings.TrimSpace(c.Name),
+	}
+	for _, arg := range c.Args {
+		ret.Args = append(ret.Args, parser.ConstructorArg{
+			Column:      strings.TrimSpace(arg.Column),
+			Id:          arg.XMLName.Local == "idArg",
+			TypeHandler: strings.TrimSpace(arg.TypeHandler),
+			JdbcType:    strings.TrimSpace(arg.JdbcType),
+		})
+	}
+	return ret
+}
+
+func (d *Discriminator) definition(namespace, parentId string) *parser.Discriminator {
+	ret := &parser.Discriminator{
+		Column:      strings.TrimSpace(d.Column),
+		TypeHandler: strings.TrimSpace(d.TypeHandler),
+		JdbcType:    strings.TrimSpace(d.JdbcType),
+	}
+	for _, c := range d.Cases {
+		dc := parser.DiscriminatorCase{
+			Value:       strings.TrimSpace(c.Value),
+			ResultType:  strings.TrimSpace(c.ResultType),
+			ResultMapId: qualifiedId(namespace, c.ResultMap),
+		}
+		if dc.ResultMapId == "" {
+			dc.ResultMap = &parser.ResultMap{
+				Id:          parentId + ".case." + dc.Value,
+				AutoMapping: true,
+			}
+			if c.ResultId.Property != "" {
+				dc.ResultMap.Results = append(dc.ResultMap.Results, c.ResultId.mapping(true))
+			}
+			for _, r := range c.Results {
+				dc.ResultMap.Results = append(dc.ResultMap.Results, r.mapping(false))
+			}
+		}
+		ret.Cases = append(ret.Cases, dc)
+	}
+	return ret
+}
+
+func nestedMappings(namespace, parentId string, nested []NestedResult) []parser.NestedMapping {
+	var ret []parser.NestedMapping
+	for _, n := range nested {
+		m := parser.NestedMapping{
+			Property:     strings.TrimSpace(n.Property),
+			ColumnPrefix: strings.TrimSpace(n.ColumnPrefix),
+			ResultMapId:  qualifiedId(namespace, n.ResultMap),
+			Select:       qualifiedId(namespace, n.Select),
+			Column:       strings.TrimSpace(n.Column),
+			Lazy:         strings.EqualFold(strings.TrimSpace(n.FetchType), "lazy"),
+		}
+		if m.ResultMapId == "" && m.Select == "" {
+			m.ResultMap = n.definition(namespace, parentId+"."+m.Property)
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// definition 内联映射的id由外层resultMap的id与属性名组成
+func (n *NestedResult) definition(namespace, id string) *parser.ResultMap {
+	typeName := n.TypeName
+	if typeName == "" {
+		typeName = n.OfType
+	}
+	ret := &parser.ResultMap{
+		Id:   id,
+		Type: typeName,
+	}
+	if n.ResultId.Property != "" {
+		ret.Results = append(ret.Results, n.ResultId.mapping(true))
+	}
+	for _, r := range n.Results {
+		ret.Results = append(ret.Results, r.mapping(false))
+	}
+	ret.Associations = nestedMappings(namespace, id, n.Associations)
+	ret.Collections = nestedMappings(namespace, id, n.Collections)
+	return ret
+}
+
+func (r Result) mapping(id bool) parser.ResultMapping {
+	column := strings.TrimSpace(r.Column)
+	property := strings.TrimSpace(r.Property)
+	if column == "" {
+		column = property
+	}
+	return parser.ResultMapping{
+		Property:    property,
+		Column:      column,
+		Id:          id,
+		TypeHandler: strings.TrimSpace(r.TypeHandler),
+		JdbcType:    strings.TrimSpace(r.JdbcType),
+	}
+}
+
+// qualifiedId 没有指定namespace的id使用当前namespace
+func qualifiedId(namespace, id string) string {
+	id = strings.TrimSpace(id)
+	if id == "" || namespace == "" || strings.Contains(id, ".") {
+		return id
+	}
+	return namespace + "." + id
 }
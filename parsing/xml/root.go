@@ -19,15 +19,19 @@ package xml
 
 import (
 	"github.com/xfali/xlog"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/xfali/gobatis/v2/parsing"
+	"github.com/xfali/gobatis/v2/parsing/parser"
 )
 
 type Mapper struct {
 	Namespace  string      `xml:"namespace,attr"`
 	ResultMaps []ResultMap `xml:"resultMap"`
 	Sql        []Sql       `xml:"sql"`
+	Cache      *Cache      `xml:"cache"`
 
 	Insert []Insert `xml:"insert"`
 	Update []Update `xml:"update"`
@@ -37,10 +41,19 @@ type Mapper struct {
 
 func (mapper *Mapper) Format() map[string]*parsing.DynamicData {
 	ret := map[string]*parsing.DynamicData{}
-	keyPre := strings.TrimSpace(mapper.Namespace)
+	ns := strings.TrimSpace(mapper.Namespace)
+	keyPre := ns
 	if keyPre != "" {
 		keyPre = keyPre + "."
 	}
+	cacheConf := mapper.cacheConfig()
+	setAttributes := func(d *parsing.DynamicData, key, timeout, flushCache, statementType string, isSelect bool) {
+		d.Attributes.Timeout = parseTimeoutAttr(key, timeout)
+		d.Attributes.StatementType = strings.ToUpper(strings.TrimSpace(statementType))
+		d.Attributes.Namespace = ns
+		d.Attributes.Cache = cacheConf
+		d.Attributes.FlushCache = parseBoolAttr(key, "flushCache", flushCache, !isSelect)
+	}
 	for _, v := range mapper.Insert {
 		key := keyPre + v.Id
 		if d, ok := ret[key]; ok {
@@ -48,6 +61,10 @@ func (mapper *Mapper) Format() map[string]*parsing.DynamicData {
 		}
 		d, err := ParseDynamic(strings.TrimSpace(v.Data), mapper.Sql)
 		if err == nil {
+			setAttributes(d, key, v.Timeout, v.FlushCache, v.StatementType, false)
+			d.Attributes.UseGeneratedKeys = parseBoolAttr(key, "useGeneratedKeys", v.UseGeneratedKeys, false)
+			d.Attributes.KeyProperty = parseListAttr(v.KeyProperty)
+			d.Attributes.KeyColumn = parseListAttr(v.KeyColumn)
 			ret[key] = d
 		}
 	}
@@ -58,6 +75,7 @@ func (mapper *Mapper) Format() map[string]*parsing.DynamicData {
 		}
 		d, err := ParseDynamic(strings.TrimSpace(v.Data), mapper.Sql)
 		if err == nil {
+			setAttributes(d, key, v.Timeout, v.FlushCache, v.StatementType, false)
 			ret[key] = d
 		}
 	}
@@ -68,6 +86,10 @@ func (mapper *Mapper) Format() map[string]*parsing.DynamicData {
 		}
 		d, err := ParseDynamic(strings.TrimSpace(v.Data), mapper.Sql)
 		if err == nil {
+			setAttributes(d, key, v.Timeout, v.FlushCache, v.StatementType, true)
+			d.Attributes.FetchSize = parseIntAttr(key, "fetchSize", v.FetchSize)
+			d.Attributes.UseCache = parseBoolAttr(key, "useCache", v.UseCache, true)
+			d.Attributes.ResultMap = qualifiedId(ns, v.ResultMap)
 			ret[key] = d
 		}
 	}
@@ -78,8 +100,79 @@ func (mapper *Mapper) Format() map[string]*parsing.DynamicData {
 		}
 		d, err := ParseDynamic(strings.TrimSpace(v.Data), mapper.Sql)
 		if err == nil {
+			setAttributes(d, key, v.Timeout, v.FlushCache, v.StatementType, false)
 			ret[key] = d
 		}
 	}
 	return ret
 }
+
+// ResultMapDefinitions 返回mapper中定义的resultMap，id为namespace.id
+func (mapper *Mapper) ResultMapDefinitions() []*parser.ResultMap {
+	ns := strings.TrimSpace(mapper.Namespace)
+	ret := make([]*parser.ResultMap, 0, len(mapper.ResultMaps))
+	for i := range mapper.ResultMaps {
+		ret = append(ret, mapper.ResultMaps[i].definition(ns))
+	}
+	return ret
+}
+
+func parseIntAttr(sqlId, name, value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		xlog.Warnf("Sql %s attribute %s is not a number: %s\n", sqlId, name, value)
+		return 0
+	}
+	return i
+}
+
+// parseTimeoutAttr timeout属性的单位为秒
+func parseTimeoutAttr(sqlId, value string) time.Duration {
+	return time.Duration(parseIntAttr(sqlId, "timeout", value)) * time.Second
+}
+
+func parseBoolAttr(sqlId, name, value string, defaultValue bool) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		xlog.Warnf("Sql %s attribute %s is not a bool: %s\n", sqlId, name, value)
+		return defaultValue
+	}
+	return b
+}
+
+// cacheConfig 解析cache元素，flushInterval的单位为毫秒
+// 缓存固定使用LRU淘汰，eviction以及readOnly属性不生效
+func (mapper *Mapper) cacheConfig() *parser.CacheConfig {
+	if mapper.Cache == nil {
+		return nil
+	}
+	if e := strings.TrimSpace(mapper.Cache.Eviction); e != "" && !strings.EqualFold(e, "LRU") {
+		xlog.Warnf("Namespace %s cache eviction %s is not supported, use LRU\n", mapper.Namespace, e)
+	}
+	if mapper.Cache.ReadOnly != "" {
+		xlog.Warnf("Namespace %s cache attribute readOnly is not supported and ignored\n", mapper.Namespace)
+	}
+	return &parser.CacheConfig{
+		Size: parseIntAttr(mapper.Namespace, "size", mapper.Cache.Size),
+		TTL:  time.Duration(parseIntAttr(mapper.Namespace, "flushInterval", mapper.Cache.FlushInterval)) * time.Millisecond,
+	}
+}
+
+// parseListAttr 解析逗号分隔的属性值
+func parseListAttr(value string) []string {
+	var ret []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
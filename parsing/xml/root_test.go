@@ -0,0 +1,260 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xml
+
+import (
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapperAttributes(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<select id="selectUser" fetchSize="100" timeout="3">SELECT * FROM tbl_user</select>
+	<update id="updateUser" timeout="5">UPDATE tbl_user SET name = #{name}</update>
+	<update id="callUser" statementType="callable">CALL update_user(#{name}, #{total,mode=OUT})</update>
+	<insert id="insertUser" useGeneratedKeys="true" keyProperty="id, code">INSERT INTO tbl_user(name) VALUES(#{name})</insert>
+	<delete id="deleteUser">DELETE FROM tbl_user</delete>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := m.Format()
+	if a := ret["test.selectUser"].Attributes; a.FetchSize != 100 || a.Timeout != 3*time.Second {
+		t.Fatalf("unexpected select attributes %+v", a)
+	}
+	if a := ret["test.updateUser"].Attributes; a.Timeout != 5*time.Second || a.StatementType != "" {
+		t.Fatalf("unexpected update attributes %+v", a)
+	}
+	if a := ret["test.callUser"].Attributes; a.StatementType != parser.StatementTypeCallable {
+		t.Fatalf("unexpected call attributes %+v", a)
+	}
+	md, err := ret["test.callUser"].ParseMetadata("mysql", map[string]interface{}{"name": "a", "total": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.PrepareSql != "CALL update_user(?, ?)" || !reflect.DeepEqual(md.Outs, []parser.OutParam{{Index: 1, Name: "total", Mode: parser.ParamModeOut}}) {
+		t.Fatalf("unexpected call metadata %s %+v", md.PrepareSql, md.Outs)
+	}
+	if a := ret["test.insertUser"].Attributes; !a.UseGeneratedKeys || !reflect.DeepEqual(a.KeyProperty, []string{"id", "code"}) {
+		t.Fatalf("unexpected insert attributes %+v", a)
+	}
+	if a := ret["test.deleteUser"].Attributes; a.Timeout != 0 {
+		t.Fatalf("unexpected delete attributes %+v", a)
+	}
+}
+
+func TestMapperCache(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<cache size="64" flushInterval="60000"/>
+	<select id="selectUser">SELECT * FROM tbl_user</select>
+	<select id="selectNoCache" useCache="false" flushCache="true">SELECT * FROM tbl_user</select>
+	<update id="updateUser">UPDATE tbl_user SET name = #{name}</update>
+	<delete id="deleteUser" flushCache="false">DELETE FROM tbl_user</delete>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := m.Format()
+	a := ret["test.selectUser"].Attributes
+	if a.Namespace != "test" || a.Cache == nil || a.Cache.Size != 64 || a.Cache.TTL != time.Minute || !a.UseCache || a.FlushCache {
+		t.Fatalf("unexpected select attributes %+v", a)
+	}
+	if a := ret["test.selectNoCache"].Attributes; a.UseCache || !a.FlushCache {
+		t.Fatalf("unexpected select attributes %+v", a)
+	}
+	if a := ret["test.updateUser"].Attributes; !a.FlushCache {
+		t.Fatalf("unexpected update attributes %+v", a)
+	}
+	if a := ret["test.deleteUser"].Attributes; a.FlushCache {
+		t.Fatalf("unexpected delete attributes %+v", a)
+	}
+}
+
+func TestMapperResultMap(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<resultMap id="userMap" type="User" autoMapping="false">
+		<id property="userId" column="id"/>
+		<result property="userName" column="name"/>
+		<result property="address.city" column="city"/>
+		<result property="tags" column="tags" jdbcType="VARCHAR" typeHandler="csv"/>
+	</resultMap>
+	<select id="selectUser" resultMap="userMap">SELECT * FROM tbl_user</select>
+	<select id="selectOther" resultMap="other.userMap">SELECT * FROM tbl_user</select>
+	<select id="selectPlain">SELECT * FROM tbl_user</select>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rms := m.ResultMapDefinitions()
+	if len(rms) != 1 {
+		t.Fatal("expect 1 result map but get ", len(rms))
+	}
+	expect := &parser.ResultMap{
+		Id:   "test.userMap",
+		Type: "User",
+		Results: []parser.ResultMapping{
+			{Property: "userId", Column: "id", Id: true},
+			{Property: "userName", Column: "name"},
+			{Property: "address.city", Column: "city"},
+			{Property: "tags", Column: "tags", TypeHandler: "csv", JdbcType: "VARCHAR"},
+		},
+	}
+	if !reflect.DeepEqual(rms[0], expect) {
+		t.Fatalf("unexpected result map %+v", rms[0])
+	}
+
+	ret := m.Format()
+	if id := ret["test.selectUser"].Attributes.ResultMap; id != "test.userMap" {
+		t.Fatal("unexpected result map id ", id)
+	}
+	if id := ret["test.selectOther"].Attributes.ResultMap; id != "other.userMap" {
+		t.Fatal("unexpected result map id ", id)
+	}
+	if id := ret["test.selectPlain"].Attributes.ResultMap; id != "" {
+		t.Fatal("unexpected result map id ", id)
+	}
+
+	// resultMap与语句注册在同一个Registry中
+	registry := parser.NewRegistry()
+	err = NewManager(registry).RegisterData([]byte(`<mapper namespace="test">
+	<resultMap id="userMap" type="User"><id property="userId" column="id"/></resultMap>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := registry.FindResultMap("test.userMap"); !ok {
+		t.Fatal("expect result map registered")
+	}
+}
+
+func TestMapperNestedResultMap(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<resultMap id="orderMap" type="Order">
+		<id property="id" column="order_id"/>
+		<association property="buyer" javaType="Buyer" columnPrefix="buyer_">
+			<id property="id" column="id"/>
+			<result property="name" column="name"/>
+		</association>
+		<collection property="items" ofType="Item" columnPrefix="item_" resultMap="itemMap"/>
+	</resultMap>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := m.ResultMapDefinitions()[0]
+	if !rm.HasNested() || len(rm.Associations) != 1 || len(rm.Collections) != 1 {
+		t.Fatalf("unexpected result map %+v", rm)
+	}
+	expect := parser.NestedMapping{
+		Property:     "buyer",
+		ColumnPrefix: "buyer_",
+		ResultMap: &parser.ResultMap{
+			Id:   "test.orderMap.buyer",
+			Type: "Buyer",
+			Results: []parser.ResultMapping{
+				{Property: "id", Column: "id", Id: true},
+				{Property: "name", Column: "name"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(rm.Associations[0], expect) {
+		t.Fatalf("unexpected association %+v", rm.Associations[0])
+	}
+	if c := rm.Collections[0]; c.ResultMapId != "test.itemMap" || c.ResultMap != nil || c.ColumnPrefix != "item_" {
+		t.Fatalf("unexpected collection %+v", c)
+	}
+}
+
+func TestMapperDiscriminator(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<resultMap id="paymentMap" type="Payment">
+		<id property="id" column="id"/>
+		<discriminator column="type" jdbcType="INTEGER" typeHandler="paymentType">
+			<case value="card" resultType="CardPayment">
+				<result property="cardNo" column="extra"/>
+			</case>
+			<case value="cash" resultMap="cashMap"/>
+		</discriminator>
+	</resultMap>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := m.ResultMapDefinitions()[0].Discriminator
+	if d == nil || d.Column != "type" || d.JdbcType != "INTEGER" || d.TypeHandler != "paymentType" || len(d.Cases) != 2 {
+		t.Fatalf("unexpected discriminator %+v", d)
+	}
+	card := d.Cases[0]
+	if card.Value != "card" || card.ResultType != "CardPayment" || card.ResultMap == nil ||
+		!reflect.DeepEqual(card.ResultMap.Results, []parser.ResultMapping{{Property: "cardNo", Column: "extra"}}) {
+		t.Fatalf("unexpected case %+v", card)
+	}
+	if cash := d.Cases[1]; cash.ResultMapId != "test.cashMap" || cash.ResultMap != nil {
+		t.Fatalf("unexpected case %+v", cash)
+	}
+}
+
+func TestMapperConstructor(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<resultMap id="moneyMap" type="Money">
+		<constructor name="money">
+			<idArg column="id"/>
+			<arg column="amount" jdbcType="DECIMAL" typeHandler="cents"/>
+			<arg column="currency"/>
+		</constructor>
+	</resultMap>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := &parser.Constructor{
+		Name: "money",
+		Args: []parser.ConstructorArg{
+			{Column: "id", Id: true},
+			{Column: "amount", TypeHandler: "cents", JdbcType: "DECIMAL"},
+			{Column: "currency"},
+		},
+	}
+	if c := m.ResultMapDefinitions()[0].Constructor; !reflect.DeepEqual(c, expect) {
+		t.Fatalf("unexpected constructor %+v", c)
+	}
+}
+
+func TestMapperNestedSelect(t *testing.T) {
+	m, err := Parse([]byte(`<mapper namespace="test">
+	<resultMap id="carMap" type="Car">
+		<id property="id" column="id"/>
+		<association property="owner" select="findUser" column="owner_id" fetchType="lazy"/>
+		<collection property="parts" select="parts.findByCar" column="{carId=id}"/>
+	</resultMap>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := m.ResultMapDefinitions()[0]
+	expect := parser.NestedMapping{Property: "owner", Select: "test.findUser", Column: "owner_id", Lazy: true}
+	if !reflect.DeepEqual(rm.Associations[0], expect) {
+		t.Fatalf("unexpected association %+v", rm.Associations[0])
+	}
+	expect = parser.NestedMapping{Property: "parts", Select: "parts.findByCar", Column: "{carId=id}"}
+	if !reflect.DeepEqual(rm.Collections[0], expect) {
+		t.Fatalf("unexpected collection %+v", rm.Collections[0])
+	}
+}
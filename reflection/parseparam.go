@@ -19,6 +19,7 @@ package reflection
 
 import (
 	"fmt"
+	"github.com/xfali/gobatis/v2/typehandler"
 	"github.com/xfali/reflection"
 	"reflect"
 	"strconv"
@@ -69,6 +70,12 @@ func (parser *paramParser) parseOne(parentKey string, v interface{}) {
 		oi, _ := reflection.GetStructInfo(v)
 		structMap := oi.MapValue()
 		for key, value := range structMap {
+			// 字段指定了typeHandler时记录处理器名称，绑定参数时使用
+			if f, ok := rt.FieldByName(oi.FieldNameMap[key]); ok {
+				if name := f.Tag.Get(typehandler.TagName); name != "" {
+					value = typehandler.Value{Handler: name, Value: value}
+				}
+			}
 			parser.ret[parentKey+structKey(oi, key)] = value
 		}
 	} else if rt.Kind() == reflect.Slice {
@@ -116,7 +123,7 @@ func (parser *paramParser) parseOne(parentKey string, v interface{}) {
 					if !value.CanInterface() {
 						value = reflect.Indirect(value)
 					}
-					parser.ret[parentKey+key.String()] = value
+					parser.ret[parentKey+key.String()] = value.Interface()
 				}
 			}
 		}
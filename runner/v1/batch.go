@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/lean/statement"
+	"reflect"
+	"time"
+)
+
+// Preparer lean session如果实现了该接口，批量执行时相同的语句共享一个预编译statement
+// 否则每组参数单独执行。factory.SqlFactory创建的session均实现了该接口
+type Preparer interface {
+	Prepare(ctx context.Context, sql string) (statement.Statement, error)
+}
+
+// Params 批量执行时的一组参数，会被展开后传入ParseMetadata
+type Params []interface{}
+
+type BatchResult struct {
+	// RowsAffected 每组参数影响的行数，顺序与传入参数一致
+	RowsAffected []int64
+	// Total 总影响行数
+	Total int64
+}
+
+type BatchRunner interface {
+	// Context 设置Context
+	Context(ctx context.Context) BatchRunner
+	// Tx 是否在事务中执行，任意一组参数执行失败则回滚
+	Tx(enable bool) BatchRunner
+	// Exec 执行批量语句
+	// items必须是slice，每个元素为一组参数，元素类型为Params时展开为多个参数
+	// 执行失败时返回已执行部分的结果以及错误
+	Exec(items interface{}) (*BatchResult, error)
+}
+
+type batchRunner struct {
+	sess   *Session
+	parser parser.Parser
+	sqlId  string
+	ctx    context.Context
+	tx     bool
+}
+
+// Batch 使用多组参数批量执行insert/update/delete语句
+func (s *Session) Batch(sql string) BatchRunner {
+	return &batchRunner{
+		sess:   s,
+		parser: s.findSqlParser(sql),
+		sqlId:  sql,
+		ctx:    s.ctx,
+	}
+}
+
+func (r *batchRunner) Context(ctx context.Context) BatchRunner {
+	r.ctx = ctx
+	return r
+}
+
+func (r *batchRunner) Tx(enable bool) BatchRunner {
+	r.tx = enable
+	return r
+}
+
+func (r *batchRunner) Exec(items interface{}) (*BatchResult, error) {
+	if r.parser == nil {
+		r.sess.logger.Warnf(errors.ParseParserNilError.Error())
+		return nil, errors.WithStatement(errors.ParseParserNilError, errors.ParseParserNilError, r.sqlId, "", r.sess.driver)
+	}
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, errors.ParseObjectNotSlice
+	}
+
+	invs := make([]*Invocation, rv.Len())
+	for i := range invs {
+		var params []interface{}
+		item := rv.Index(i).Interface()
+		if p, ok := item.(Params); ok {
+			params = p
+		} else {
+			params = []interface{}{item}
+		}
+		now := time.Now()
+		md, err := r.parser.ParseMetadata(r.sess.driver, params...)
+		renderTime := time.Since(now)
+		if err != nil {
+			r.sess.logger.Warnf("batch item %d parse failed: %v\n", i, err)
+			return nil, errors.WithStatement(err, errors.ParseDynamicSqlError, r.sqlId, "", r.sess.driver)
+		}
+		invs[i] = &Invocation{
+			Session:    r.sess,
+			SqlId:      r.sqlId,
+			Driver:     r.sess.driver,
+			Params:     params,
+			Metadata:   md,
+			RenderTime: renderTime,
+		}
+	}
+
+	ret := &BatchResult{
+		RowsAffected: make([]int64, 0, len(invs)),
+	}
+	if !r.tx {
+		return ret, r.execute(r.ctx, invs, ret)
+	}
+	err := r.sess.Tx(r.ctx, func(session *Session) error {
+		return r.execute(r.ctx, invs, ret)
+	})
+	return ret, err
+}
+
+func (r *batchRunner) execute(ctx context.Context, invs []*Invocation, ret *BatchResult) error {
+	preparer, canPrepare := r.sess.session.(Preparer)
+	stmts := map[string]statement.Statement{}
+	defer func() {
+		for _, stmt := range stmts {
+			if err := stmt.Close(); err != nil {
+				r.sess.logger.Warnln(err)
+			}
+		}
+	}()
+
+	invoker := r.sess.wrapInvoker(func(ctx context.Context, inv *Invocation) error {
+		var (
+			result resultset.Result
+			err    error
+		)
+		md := inv.Metadata
+		if err = r.sess.checkWritable(md); err != nil {
+			return err
+		}
+		if canPrepare {
+			stmt, ok := stmts[md.PrepareSql]
+			if !ok {
+				stmt, err = preparer.Prepare(ctx, md.PrepareSql)
+				if err != nil {
+					r.sess.logger.Warnln(err)
+					return err
+				}
+				stmts[md.PrepareSql] = stmt
+			}
+			result, err = stmt.Execute(ctx, md.Params...)
+		} else {
+			result, err = r.sess.session.Execute(ctx, md.PrepareSql, md.Params...)
+		}
+		if err != nil {
+			r.sess.logger.Warnln(err)
+			return err
+		}
+		defer result.Close()
+		inv.RowsAffected, err = result.RowsAffected()
+		if err != nil {
+			r.sess.logger.Warnln(err)
+		}
+		return nil
+	})
+
+	for _, inv := range invs {
+		if err := r.invoke(ctx, invoker, inv); err != nil {
+			return err
+		}
+		ret.RowsAffected = append(ret.RowsAffected, inv.RowsAffected)
+		ret.Total += inv.RowsAffected
+	}
+	return nil
+}
+
+func (r *batchRunner) invoke(ctx context.Context, invoker Invoker, inv *Invocation) error {
+	ctx, cancel := r.sess.statementContext(ctx, inv.Metadata)
+	defer cancel()
+	err := invoker(ctx, inv)
+	r.sess.flushCache(inv.Metadata)
+	r.sess.ClearLocalCache()
+	return errors.WithStatement(timeoutError(ctx, err), errors.StatementExecError, r.sqlId, inv.Metadata.PrepareSql, inv.Driver)
+}
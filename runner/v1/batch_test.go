@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/xfali/gobatis/v2/database/factory"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/lean/statement"
+	"testing"
+)
+
+type preparedSession struct {
+	*testSession
+	prepared []string
+}
+
+func (s *preparedSession) Prepare(ctx context.Context, sql string) (statement.Statement, error) {
+	s.prepared = append(s.prepared, sql)
+	return &testStatement{sess: s.testSession, sql: sql}, nil
+}
+
+type testStatement struct {
+	sess *testSession
+	sql  string
+}
+
+func (s *testStatement) Query(ctx context.Context, params ...interface{}) (resultset.Result, error) {
+	return s.sess.Query(ctx, s.sql, params...)
+}
+
+func (s *testStatement) Execute(ctx context.Context, params ...interface{}) (resultset.Result, error) {
+	return s.sess.Execute(ctx, s.sql, params...)
+}
+
+func (s *testStatement) Close() error {
+	return nil
+}
+
+func TestBatch(t *testing.T) {
+	items := []testRow{{1, "a"}, {2, "b"}, {3, "c"}}
+	sql := "INSERT INTO tbl_user(id, name) VALUES(#{testRow.Id}, #{testRow.Name})"
+
+	t.Run("prepared", func(t *testing.T) {
+		ts := &preparedSession{testSession: newTestSession(nil)}
+		sess := newTestSqlSession(ts, "mysql")
+		ret, err := sess.Batch(sql).Exec(items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ret.Total != 3 || len(ret.RowsAffected) != 3 {
+			t.Fatal("expect 3 rows affected but get ", ret)
+		}
+		if len(ts.prepared) != 1 || len(ts.executed) != 3 {
+			t.Fatal("expect 1 prepared statement and 3 executions but get ", ts.prepared, ts.executed)
+		}
+		if ts.params[2][0] != int64(3) || ts.params[2][1] != "c" {
+			t.Fatal("expect params [3 c] but get ", ts.params[2])
+		}
+	})
+
+	t.Run("params", func(t *testing.T) {
+		ts := newTestSession(nil)
+		sess := newTestSqlSession(ts, "mysql")
+		ret, err := sess.Batch("DELETE FROM tbl_user WHERE id = #{0} AND name = #{1}").Exec([]Params{{1, "a"}, {2, "b"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ret.Total != 2 || len(ts.executed) != 2 {
+			t.Fatal("expect 2 executions but get ", ts.executed)
+		}
+	})
+
+	t.Run("not slice", func(t *testing.T) {
+		sess := newTestSqlSession(newTestSession(nil), "mysql")
+		_, err := sess.Batch(sql).Exec(items[0])
+		if err == nil {
+			t.Fatal("expect error")
+		}
+	})
+}
+
+// countDriver 记录Prepare以及Exec次数的database/sql驱动
+type countDriver struct {
+	prepared []string
+	executed int
+}
+
+func (d *countDriver) Open(name string) (driver.Conn, error) {
+	return &countConn{d: d}, nil
+}
+
+type countConn struct {
+	d *countDriver
+}
+
+func (c *countConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.prepared = append(c.d.prepared, query)
+	return &countStmt{d: c.d}, nil
+}
+
+func (c *countConn) Close() error {
+	return nil
+}
+
+func (c *countConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not support")
+}
+
+// ExecContext 非预编译方式执行时不调用Prepare
+func (c *countConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.executed++
+	return driver.RowsAffected(1), nil
+}
+
+type countStmt struct {
+	d *countDriver
+}
+
+func (s *countStmt) Close() error {
+	return nil
+}
+
+func (s *countStmt) NumInput() int {
+	return -1
+}
+
+func (s *countStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.executed++
+	return driver.RowsAffected(1), nil
+}
+
+func (s *countStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not support")
+}
+
+var testCountDriver = &countDriver{}
+
+func init() {
+	sql.Register("gobatis_batch_count", testCountDriver)
+}
+
+func TestBatchSqlFactory(t *testing.T) {
+	sm := NewSessionManager(factory.NewSqlFactory("gobatis_batch_count", ""))
+	if err := sm.conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer sm.Close()
+
+	items := []testRow{{1, "a"}, {2, "b"}, {3, "c"}}
+	ret, err := sm.NewSession().Batch("INSERT INTO tbl_user(id, name) VALUES(#{testRow.Id}, #{testRow.Name})").Exec(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Total != 3 {
+		t.Fatal("expect 3 rows affected but get ", ret)
+	}
+	if len(testCountDriver.prepared) != 1 || testCountDriver.executed != 3 {
+		t.Fatal("expect 1 prepared statement and 3 executions but get ", testCountDriver.prepared, testCountDriver.executed)
+	}
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/xfali/gobatis/v2/cache"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheRegistry 管理各个namespace的二级缓存
+type cacheRegistry struct {
+	factory cache.Factory
+	caches  map[string]cache.Cache
+	lock    sync.Mutex
+}
+
+func newCacheRegistry(factory cache.Factory) *cacheRegistry {
+	return &cacheRegistry{
+		factory: factory,
+		caches:  map[string]cache.Cache{},
+	}
+}
+
+func (r *cacheRegistry) get(namespace string, conf *parser.CacheConfig) cache.Cache {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	c, ok := r.caches[namespace]
+	if !ok {
+		c = r.factory(namespace, conf.Size, conf.TTL)
+		r.caches[namespace] = c
+	}
+	return c
+}
+
+func (r *cacheRegistry) clear(namespace string) {
+	r.lock.Lock()
+	c, ok := r.caches[namespace]
+	r.lock.Unlock()
+
+	if ok {
+		c.Clear()
+	}
+}
+
+// SetCacheFactory 设置二级缓存后端，需要在执行语句之前设置，nil表示使用进程内的LRU缓存
+func (sm *SessionManager) SetCacheFactory(factory cache.Factory) {
+	if factory == nil {
+		factory = cache.DefaultFactory
+	}
+	sm.caches.lock.Lock()
+	defer sm.caches.lock.Unlock()
+
+	sm.caches.factory = factory
+	sm.caches.caches = map[string]cache.Cache{}
+}
+
+// ClearCache 清空namespace的二级缓存
+func (sm *SessionManager) ClearCache(namespace string) {
+	sm.caches.clear(namespace)
+}
+
+// cachedRows 缓存的查询结果，命中时重新经过mapping解析到bean中
+type cachedRows struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func (c *cachedRows) result() resultset.Result {
+	return resultset.NewSliceResult(c.rows, c.columns, func(d interface{}, columns []string, dest []interface{}) error {
+		row := d.([]interface{})
+		for i := range dest {
+			v := row[i]
+			if b, ok := v.([]byte); ok {
+				v = append([]byte(nil), b...)
+			}
+			*(dest[i].(*interface{})) = v
+		}
+		return nil
+	})
+}
+
+func readRows(ret resultset.QueryResult) (*cachedRows, error) {
+	columns, err := ret.Columns()
+	if err != nil {
+		return nil, err
+	}
+	c := &cachedRows{columns: columns}
+	for ret.Next() {
+		row := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := ret.Scan(dest...); err != nil {
+			return nil, err
+		}
+		c.rows = append(c.rows, row)
+	}
+	// 迭代因驱动或网络错误提前结束时不能返回（进而缓存）不完整的结果
+	if re, ok := ret.(rowsErr); ok {
+		if err := re.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// selectCache 获得select语句可以使用的二级缓存
+// 事务中修改过的namespace在提交之前不使用缓存
+func (s *Session) selectCache(md *parser.Metadata) cache.Cache {
+	if s == nil || s.caches == nil || md == nil {
+		return nil
+	}
+	attrs := md.Attributes
+	if attrs.Cache == nil || attrs.Namespace == "" || !attrs.UseCache || attrs.FlushCache {
+		return nil
+	}
+	if _, ok := s.pendingFlush[attrs.Namespace]; ok {
+		return nil
+	}
+	return s.caches.get(attrs.Namespace, attrs.Cache)
+}
+
+// flushCache 清空语句所属namespace的二级缓存，事务中延迟到提交之后
+func (s *Session) flushCache(md *parser.Metadata) {
+	if s == nil || s.caches == nil || md == nil {
+		return
+	}
+	attrs := md.Attributes
+	if !attrs.FlushCache || attrs.Namespace == "" {
+		return
+	}
+	if s.txDepth > 0 {
+		if s.pendingFlush == nil {
+			s.pendingFlush = map[string]struct{}{}
+		}
+		s.pendingFlush[attrs.Namespace] = struct{}{}
+		return
+	}
+	s.caches.clear(attrs.Namespace)
+}
+
+// commitCacheFlush 事务结束后清空事务中修改过的namespace的缓存，rollback为true时丢弃
+// 同时清空session的一级缓存
+func (s *Session) commitCacheFlush(rollback bool) {
+	s.ClearLocalCache()
+	if !rollback && s.caches != nil {
+		for ns := range s.pendingFlush {
+			s.caches.clear(ns)
+		}
+	}
+	s.pendingFlush = nil
+}
+
+func cacheKey(inv *Invocation) string {
+	return paramsKey(inv.SqlId+"\x00"+inv.Metadata.PrepareSql, inv.Metadata.Params)
+}
+
+// paramsKey 使用生成的sql语句以及参数值作为缓存key
+func paramsKey(prefix string, params []interface{}) string {
+	b := strings.Builder{}
+	b.WriteString(prefix)
+	for _, p := range params {
+		p = paramValue(p)
+		b.WriteByte(0)
+		if t, ok := p.(time.Time); ok {
+			// 忽略单调时钟读数
+			p = t.Format(time.RFC3339Nano)
+		}
+		b.WriteString(fmt.Sprintf("%T:%v", p, p))
+	}
+	return b.String()
+}
+
+// paramValue 指针以及driver.Valuer使用实际传给驱动的值，避免相同的值因为地址不同而无法命中缓存
+func paramValue(p interface{}) interface{} {
+	for p != nil {
+		rv := reflect.ValueOf(p)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+		if v, ok := p.(driver.Valuer); ok {
+			dv, err := v.Value()
+			if err != nil {
+				return p
+			}
+			return dv
+		}
+		if rv.Kind() != reflect.Ptr {
+			return p
+		}
+		p = rv.Elem().Interface()
+	}
+	return nil
+}
+
+// queryCache 使用二级缓存执行查询，未命中时读取全部结果并缓存
+func (r *SelectRunner) queryCache(ctx context.Context, inv *Invocation, c cache.Cache) error {
+	key := cacheKey(inv)
+	v, ok := c.Get(key)
+	if !ok {
+		rows, err := r.fetchRows(ctx, inv)
+		if err != nil {
+			r.logger.Warnln(err)
+			return err
+		}
+		c.Put(key, rows)
+		v = rows
+	}
+
+	var err error
+	inv.RowsAffected, err = scanRows(ctx, r.sess, inv.Bean, v.(*cachedRows).result(), inv.Metadata.Attributes.ResultMap)
+	if err != nil {
+		r.logger.Warnln(err)
+	}
+	return err
+}
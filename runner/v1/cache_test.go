@@ -0,0 +1,187 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/xfali/gobatis/v2/cache"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"testing"
+	"time"
+)
+
+func newCacheTestSession(ts *testSession) *Session {
+	sess := newTestSqlSession(ts, "mysql")
+	sess.caches = newCacheRegistry(cache.DefaultFactory)
+	return sess
+}
+
+func cacheAttrParser(t *testing.T, sess *Session, sql string, attrs parser.Attributes) parser.Parser {
+	p, err := sess.ParserFactory(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs.Namespace = "user"
+	attrs.Cache = &parser.CacheConfig{Size: 16}
+	return &attrParser{Parser: p, attrs: attrs}
+}
+
+func countQueries(ts *testSession) int {
+	n := 0
+	for _, s := range ts.executed {
+		if s == "SELECT id, name FROM tbl_user WHERE id = ?" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSecondLevelCache(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	sess := newCacheTestSession(ts)
+	sel := cacheAttrParser(t, sess, "SELECT id, name FROM tbl_user WHERE id = #{0}", parser.Attributes{UseCache: true})
+	upd := cacheAttrParser(t, sess, "UPDATE tbl_user SET name = #{0}", parser.Attributes{FlushCache: true})
+
+	query := func(id int64) []testRow {
+		var rows []testRow
+		if err := sess.createSelect("user.select", sel).Param(id).Result(&rows); err != nil {
+			t.Fatal(err)
+		}
+		return rows
+	}
+
+	for i := 0; i < 3; i++ {
+		rows := query(1)
+		if len(rows) != 1 || rows[0].Id != 1 || rows[0].Name != "a" {
+			t.Fatalf("unexpected rows %+v", rows)
+		}
+	}
+	if n := countQueries(ts); n != 1 {
+		t.Fatal("expect 1 query but get ", n)
+	}
+	query(2)
+	if n := countQueries(ts); n != 2 {
+		t.Fatal("different params must not hit cache, queries: ", n)
+	}
+
+	var count int64
+	if err := sess.createUpdate("user.update", upd).Param("b").Result(&count); err != nil {
+		t.Fatal(err)
+	}
+	query(1)
+	if n := countQueries(ts); n != 3 {
+		t.Fatal("expect cache flushed by update, queries: ", n)
+	}
+
+	noCache := cacheAttrParser(t, sess, "SELECT id, name FROM tbl_user WHERE id = #{0}", parser.Attributes{UseCache: false})
+	var rows []testRow
+	if err := sess.createSelect("user.select", noCache).Param(int64(1)).Result(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if n := countQueries(ts); n != 4 {
+		t.Fatal("useCache=false must not hit cache, queries: ", n)
+	}
+}
+
+func TestSecondLevelCacheTx(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	sess := newCacheTestSession(ts)
+	sel := cacheAttrParser(t, sess, "SELECT id, name FROM tbl_user WHERE id = #{0}", parser.Attributes{UseCache: true})
+	upd := cacheAttrParser(t, sess, "UPDATE tbl_user SET name = #{0}", parser.Attributes{FlushCache: true})
+	query := func(s *Session) {
+		var rows []testRow
+		if err := s.createSelect("user.select", sel).Param(int64(1)).Result(&rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query(sess)
+	c := sess.caches.get("user", &parser.CacheConfig{})
+	err := sess.Tx(context.Background(), func(s *Session) error {
+		var count int64
+		if err := s.createUpdate("user.update", upd).Param("b").Result(&count); err != nil {
+			return err
+		}
+		// 提交之前其他session仍然可以读取缓存，当前事务不使用缓存
+		if _, ok := c.Get(cacheKeyOf(t, s, sel)); !ok {
+			t.Fatal("cache must not be flushed before commit")
+		}
+		query(s)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countQueries(ts); n != 2 {
+		t.Fatal("expect query in tx bypass cache, queries: ", n)
+	}
+	if _, ok := c.Get(cacheKeyOf(t, sess, sel)); ok {
+		t.Fatal("cache must be flushed after commit")
+	}
+}
+
+func TestSecondLevelCacheRowsErr(t *testing.T) {
+	// 读取第一行之后连接中断，结果不完整
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	ts.rowsErr = errors.New("broken connection")
+	sess := newCacheTestSession(ts)
+	sel := cacheAttrParser(t, sess, "SELECT id, name FROM tbl_user WHERE id = #{0}", parser.Attributes{UseCache: true})
+
+	var rows []testRow
+	if err := sess.createSelect("user.select", sel).Param(int64(1)).Result(&rows); !errors.Is(err, ts.rowsErr) {
+		t.Fatal("expect rows error but get ", err)
+	}
+	c := sess.caches.get("user", &parser.CacheConfig{})
+	if _, ok := c.Get(cacheKeyOf(t, sess, sel)); ok {
+		t.Fatal("partial result must not be cached")
+	}
+
+	ts.rowsErr = nil
+	if err := sess.createSelect("user.select", sel).Param(int64(1)).Result(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if n := countQueries(ts); n != 2 {
+		t.Fatal("expect query after failure, queries: ", n)
+	}
+}
+
+func cacheKeyOf(t *testing.T, sess *Session, p parser.Parser) string {
+	md, err := p.ParseMetadata(sess.driver, int64(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cacheKey(&Invocation{SqlId: "user.select", Metadata: md})
+}
+
+func TestParamsKey(t *testing.T) {
+	a, b := int64(1), int64(1)
+	name := "a"
+	now := time.Now()
+	if paramsKey("sql", []interface{}{&a, &name, now}) != paramsKey("sql", []interface{}{&b, "a", now.Round(0)}) {
+		t.Fatal("expect same key for same values")
+	}
+	var nilPtr *int64
+	if paramsKey("sql", []interface{}{&a}) == paramsKey("sql", []interface{}{int64(2)}) || paramsKey("sql", []interface{}{nilPtr}) != paramsKey("sql", []interface{}{nil}) {
+		t.Fatal("unexpected key")
+	}
+	if paramsKey("sql", []interface{}{sql.NullString{String: "a", Valid: true}}) != paramsKey("sql", []interface{}{"a"}) {
+		t.Fatal("expect driver.Valuer use its value")
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/reflection"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CallRunner 执行存储过程
+// OUT以及INOUT参数使用#{name,mode=OUT}标记，以sql.Out绑定，执行后写回参数
+// 写回的参数必须为指针，或者struct指针中的字段
+type CallRunner struct {
+	BaseRunner
+}
+
+type outParam struct {
+	target reflect.Value
+	dest   reflect.Value
+}
+
+// Call 执行存储过程，Result的bean为nil时只执行，否则读取存储过程返回的结果集
+// 只拒绝显式声明为其他statementType的语句（返回StatementNotCallable），未设置statementType的语句允许执行，
+// 直接使用的sql语句以及tpl模板无法声明statementType
+func (s *Session) Call(sql string) Runner {
+	return s.createCall(sql, s.findSqlParser(sql))
+}
+
+func (s *Session) createCall(sqlId string, parser parser.Parser) Runner {
+	ret := &CallRunner{}
+	s.initRunner(&ret.BaseRunner, sqlId, "", parser, ret)
+	return ret
+}
+
+func (r *CallRunner) Result(bean interface{}) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	md := *r.metadata
+	// 未设置statementType时不做限制
+	if st := md.Attributes.StatementType; st != "" && st != parser.StatementTypeCallable {
+		return r.wrapError(errors.StatementNotCallable, errors.StatementNotCallable, md.PrepareSql)
+	}
+	outs, err := bindOuts(&md, r.params)
+	if err != nil {
+		return r.wrapError(err, errors.CallOutParamInvalid, md.PrepareSql)
+	}
+	if err := r.invokeWith(&md, bean, r.call); err != nil {
+		return err
+	}
+	for _, o := range outs {
+		o.target.Set(o.dest.Elem())
+	}
+	return nil
+}
+
+func (r *CallRunner) call(ctx context.Context, inv *Invocation) error {
+	if reflection.IsNil(inv.Bean) {
+		return r.execute(ctx, inv)
+	}
+	if err := r.sess.checkWritable(inv.Metadata); err != nil {
+		return err
+	}
+	ret, err := r.session.Query(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
+	if err != nil {
+		r.logger.Warnln(err)
+		return err
+	}
+	// OUT参数在结果集关闭之后才可用
+	defer ret.Close()
+	inv.RowsAffected, err = scanRows(ctx, r.sess, inv.Bean, ret, inv.Metadata.Attributes.ResultMap)
+	if err != nil {
+		r.logger.Warnln(err)
+	}
+	return err
+}
+
+// bindOuts 将OUT以及INOUT参数替换为sql.Out，返回执行后需要写回的参数
+func bindOuts(md *parser.Metadata, params []interface{}) ([]outParam, error) {
+	if len(md.Outs) == 0 {
+		return nil, nil
+	}
+	md.Params = append([]interface{}(nil), md.Params...)
+	ret := make([]outParam, 0, len(md.Outs))
+	for _, o := range md.Outs {
+		target, ok := outTarget(params, o.Name)
+		if !ok {
+			xlog.Warnf("OUT parameter %s must be a pointer or a field of struct pointer\n", o.Name)
+			return nil, errors.CallOutParamInvalid
+		}
+		inout := o.Mode == parser.ParamModeInOut
+		dest := reflect.New(target.Type())
+		if inout {
+			dest.Elem().Set(target)
+		}
+		md.Params[o.Index] = sql.Out{Dest: dest.Interface(), In: inout}
+		ret = append(ret, outParam{target: target, dest: dest})
+	}
+	return ret, nil
+}
+
+// outTarget 按照参数名查找可以写回的值，参数名的规则与解析参数时一致：
+// 简单类型按照序号，struct为结构体名.字段名（或者alias tag）
+func outTarget(params []interface{}, name string) (reflect.Value, bool) {
+	index := 0
+	for _, p := range params {
+		rv := reflect.ValueOf(p)
+		if !rv.IsValid() {
+			continue
+		}
+		t := rv.Type()
+		isPtr := t.Kind() == reflect.Ptr
+		if isPtr {
+			t = t.Elem()
+		}
+		switch {
+		case reflection.IsSimpleType(t) || t.Kind() == reflect.Slice:
+			if strconv.Itoa(index) == name {
+				if !isPtr || rv.IsNil() || t.Kind() == reflect.Slice {
+					return reflect.Value{}, false
+				}
+				return rv.Elem(), true
+			}
+			index++
+		case t.Kind() == reflect.Struct:
+			prefix := t.Name() + "."
+			if !isPtr || rv.IsNil() || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			oi, err := reflection.GetStructInfo(p)
+			if err != nil {
+				continue
+			}
+			if field, ok := oi.FieldNameMap[name[len(prefix):]]; ok {
+				v := rv.Elem().FieldByName(field)
+				return v, v.CanSet()
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
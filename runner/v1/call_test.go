@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"reflect"
+	"testing"
+)
+
+type transfer struct {
+	From    int64
+	Amount  int64
+	Balance int64
+	Seq     int64 `alias:"seq"`
+}
+
+// fillOuts 模拟驱动写入OUT参数，INOUT参数在输入值的基础上加1
+func fillOuts(values ...int64) Interceptor {
+	return InterceptorFunc(func(ctx context.Context, inv *Invocation, next Invoker) error {
+		i := 0
+		for _, p := range inv.Metadata.Params {
+			if out, ok := p.(sql.Out); ok {
+				dest := out.Dest.(*int64)
+				if out.In {
+					*dest++
+				} else {
+					*dest = values[i]
+				}
+				i++
+			}
+		}
+		return next(ctx, inv)
+	})
+}
+
+func TestCall(t *testing.T) {
+	conn := &testConnection{}
+	sm := newTestSessionManager(conn, "mysql")
+	sm.AddInterceptor(fillOuts(100, 200))
+	sess := sm.NewSession()
+	ts := conn.sessions[0]
+
+	t.Run("struct", func(t *testing.T) {
+		tr := &transfer{From: 1, Amount: 10, Seq: 5}
+		err := sess.Call("CALL do_transfer(#{transfer.From}, #{transfer.Amount}, #{transfer.Balance, mode=OUT}, #{transfer.seq,mode=INOUT})").Param(tr).Result(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tr.Balance != 100 || tr.Seq != 6 {
+			t.Fatalf("unexpected out params %+v", tr)
+		}
+		params := ts.params[len(ts.params)-1]
+		if ts.executed[len(ts.executed)-1] != "CALL do_transfer(?, ?, ?, ?)" || params[0] != int64(1) || params[1] != int64(10) {
+			t.Fatalf("unexpected call %s %v", ts.executed[len(ts.executed)-1], params)
+		}
+		if out, ok := params[2].(sql.Out); !ok || out.In {
+			t.Fatalf("expect OUT param but get %v", params[2])
+		}
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		var total int64
+		err := sess.Call("CALL count_user(#{0}, #{1,mode=OUT})").Param("x", &total).Result(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 100 {
+			t.Fatal("expect 100 but get ", total)
+		}
+	})
+
+	t.Run("result set", func(t *testing.T) {
+		ts.columns = []string{"id", "name"}
+		ts.rows = [][]interface{}{{int64(1), "a"}, {int64(2), "b"}}
+		defer func() {
+			ts.columns, ts.rows = nil, nil
+		}()
+		var total int64
+		var rows []testRow
+		err := sess.Call("CALL list_user(#{0,mode=OUT})").Param(&total).Result(&rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 100 || !reflect.DeepEqual(rows, []testRow{{1, "a"}, {2, "b"}}) {
+			t.Fatalf("unexpected result %d %+v", total, rows)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := sess.Call("CALL count_user(#{0,mode=OUT})").Param(int64(1)).Result(nil)
+		if !errors.Is(err, errors.CallOutParamInvalid) {
+			t.Fatal("expect CallOutParamInvalid but get ", err)
+		}
+		err = sess.Call("CALL do_transfer(#{transfer.Balance,mode=OUT})").Param(transfer{}).Result(nil)
+		if !errors.Is(err, errors.CallOutParamInvalid) {
+			t.Fatal("expect CallOutParamInvalid but get ", err)
+		}
+	})
+
+	t.Run("statement type", func(t *testing.T) {
+		stmt := "CALL count_user(#{0})"
+		p, err := sess.ParserFactory(stmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sess.createCall(stmt, &attrParser{Parser: p, attrs: parser.Attributes{StatementType: parser.StatementTypePrepared}}).Param("x").Result(nil)
+		if !errors.Is(err, errors.StatementNotCallable) {
+			t.Fatal("expect StatementNotCallable but get ", err)
+		}
+		err = sess.createCall(stmt, &attrParser{Parser: p, attrs: parser.Attributes{StatementType: parser.StatementTypeCallable}}).Param("x").Result(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 未设置statementType时允许执行
+		err = sess.createCall(stmt, &attrParser{Parser: p, attrs: parser.Attributes{}}).Param("x").Result(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/manager"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/reflection"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+type constructorFunc struct {
+	fn     reflect.Value
+	args   []reflect.Type
+	hasErr bool
+}
+
+// RegisterConstructor 在全局解析器注册表中注册resultMap中constructor引用的构造函数
+// fn必须为函数，返回结果对象，或者结果对象以及error，列值会被转换为对应参数的类型
+func RegisterConstructor(name string, fn interface{}) error {
+	return registerConstructor(manager.GetGlobalParserRegistry(), name, fn)
+}
+
+// RegisterConstructor 在SessionManager使用的解析器注册表中注册构造函数
+func (sm *SessionManager) RegisterConstructor(name string, fn interface{}) error {
+	return registerConstructor(sm.registry, name, fn)
+}
+
+func registerConstructor(registry parser.Registry, name string, fn interface{}) error {
+	if _, err := newConstructorFunc(fn); err != nil {
+		return err
+	}
+	r, ok := registry.(parser.ResultMapRegistry)
+	if !ok {
+		return errors.ResultMapRegistryNotSupport
+	}
+	r.AddConstructor(name, fn)
+	return nil
+}
+
+func newConstructorFunc(fn interface{}) (*constructorFunc, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, errors.ConstructorInvalid
+	}
+	t := v.Type()
+	if t.IsVariadic() || t.NumOut() == 0 || t.NumOut() > 2 || (t.NumOut() == 2 && t.Out(1) != errorType) {
+		return nil, errors.ConstructorInvalid
+	}
+	c := &constructorFunc{
+		fn:     v,
+		args:   make([]reflect.Type, t.NumIn()),
+		hasErr: t.NumOut() == 2,
+	}
+	for i := range c.args {
+		c.args[i] = t.In(i)
+	}
+	return c, nil
+}
+
+func (s *Session) findConstructor(name string) (*constructorFunc, bool) {
+	r, ok := s.registry.(parser.ResultMapRegistry)
+	if !ok {
+		return nil, false
+	}
+	fn, ok := r.FindConstructor(name)
+	if !ok {
+		return nil, false
+	}
+	c, err := newConstructorFunc(fn)
+	return c, err == nil
+}
+
+// call 使用列值调用构造函数，NULL转换为参数类型的零值
+// 与列映射相同，优先使用参数指定的处理器，其次使用按照参数类型注册的处理器
+func (c *constructorFunc) call(defs []parser.ConstructorArg, values []interface{}) (reflect.Value, error) {
+	args := make([]reflect.Value, len(c.args))
+	for i, t := range c.args {
+		args[i] = reflect.New(t).Elem()
+		if values[i] == nil {
+			continue
+		}
+		if ok, err := typehandler.Scan(values[i], args[i], defs[i].TypeHandler, defs[i].JdbcType); ok || err != nil {
+			if err != nil {
+				xlog.Warnf("constructor argument %d type handler failed: %v\n", i, err)
+				return reflect.Value{}, err
+			}
+			continue
+		}
+		if !setArg(args[i], values[i]) {
+			xlog.Warnf("constructor argument %d cannot set value %v\n", i, values[i])
+			return reflect.Value{}, errors.ResultSetValueFailed
+		}
+	}
+	out := c.fn.Call(args)
+	if c.hasErr && !out[1].IsNil() {
+		return reflect.Value{}, out[1].Interface().(error)
+	}
+	return out[0], nil
+}
+
+func setArg(dst reflect.Value, value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(dst.Type()) {
+		dst.Set(v)
+		return true
+	}
+	if dst.Kind() == reflect.Ptr {
+		pv := reflect.New(dst.Type().Elem())
+		if !reflection.SetValue(pv.Elem(), v) {
+			return false
+		}
+		dst.Set(pv)
+		return true
+	}
+	return reflection.SetValue(dst, v)
+}
+
+// constructedMapper 使用构造函数创建对象，之后再应用result映射，不进行自动映射
+type constructedMapper struct {
+	ctor    *constructorFunc
+	defs    []parser.ConstructorArg
+	args    []int
+	results *rowMapper
+}
+
+func newConstructedMapper(sess *Session, rm *parser.ResultMap, columns []string) (*constructedMapper, error) {
+	ctor, ok := sess.findConstructor(rm.Constructor.Name)
+	if !ok {
+		xlog.Warnf("result map %s constructor %s is not registered\n", rm.Id, rm.Constructor.Name)
+		return nil, errors.ResultMapInvalid
+	}
+	if len(ctor.args) != len(rm.Constructor.Args) {
+		xlog.Warnf("result map %s constructor %s needs %d arguments but get %d\n", rm.Id, rm.Constructor.Name, len(ctor.args), len(rm.Constructor.Args))
+		return nil, errors.ResultMapInvalid
+	}
+	m := &constructedMapper{
+		ctor: ctor,
+		defs: rm.Constructor.Args,
+		args: make([]int, len(rm.Constructor.Args)),
+		results: newRowMapper(&parser.ResultMap{
+			Id:      rm.Id,
+			Results: rm.Results,
+		}, columns),
+	}
+	for i, arg := range rm.Constructor.Args {
+		m.args[i] = -1
+		for j, c := range columns {
+			if strings.EqualFold(c, arg.Column) {
+				m.args[i] = j
+				break
+			}
+		}
+		if m.args[i] < 0 {
+			xlog.Warnf("result map %s constructor column %s not found\n", rm.Id, arg.Column)
+			return nil, errors.ResultNameNotFound
+		}
+	}
+	return m, nil
+}
+
+// newValue 创建对象并转换为et类型
+func (m *constructedMapper) newValue(et reflect.Type, values []interface{}) (reflect.Value, error) {
+	args := make([]interface{}, len(m.args))
+	for i, idx := range m.args {
+		args[i] = values[idx]
+	}
+	obj, err := m.ctor.call(m.defs, args)
+	if err != nil {
+		return obj, err
+	}
+	if len(m.results.rm.Results) > 0 {
+		if obj, err = m.mapResults(obj, values); err != nil {
+			return obj, err
+		}
+	}
+
+	switch {
+	case obj.Type().AssignableTo(et):
+		return obj, nil
+	case obj.Kind() == reflect.Ptr && !obj.IsNil() && obj.Elem().Type().AssignableTo(et):
+		return obj.Elem(), nil
+	case reflect.PtrTo(obj.Type()).AssignableTo(et):
+		pv := reflect.New(obj.Type())
+		pv.Elem().Set(obj)
+		return pv, nil
+	}
+	xlog.Warnf("constructor result %s is not assignable to %s\n", obj.Type(), et)
+	return reflect.Value{}, errors.ResultMapInvalid
+}
+
+func (m *constructedMapper) mapResults(obj reflect.Value, values []interface{}) (reflect.Value, error) {
+	if obj.Kind() == reflect.Ptr {
+		if obj.IsNil() || obj.Elem().Kind() != reflect.Struct {
+			return obj, nil
+		}
+		return obj, m.results.mapRow(obj.Elem(), values)
+	}
+	if obj.Kind() != reflect.Struct {
+		return obj, nil
+	}
+	pv := reflect.New(obj.Type())
+	pv.Elem().Set(obj)
+	err := m.results.mapRow(pv.Elem(), values)
+	return pv.Elem(), err
+}
+
+func scanConstructed(sess *Session, rv reflect.Value, isSlice bool, et reflect.Type, result resultset.QueryResult, rm *parser.ResultMap) (int64, error) {
+	columns, err := result.Columns()
+	if err != nil {
+		return 0, err
+	}
+	m, err := newConstructedMapper(sess, rm, columns)
+	if err != nil {
+		return 0, err
+	}
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var count int64
+	for result.Next() {
+		if err := result.Scan(dest...); err != nil {
+			return count, err
+		}
+		ev, err := m.newValue(et, values)
+		if err != nil {
+			return count, err
+		}
+		count++
+		if !isSlice {
+			rv.Set(ev)
+			break
+		}
+		rv.Set(reflect.Append(rv, ev))
+	}
+	return count, nil
+}
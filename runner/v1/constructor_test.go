@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"strconv"
+	"testing"
+)
+
+type money struct {
+	amount   int64
+	currency string
+	Note     string
+}
+
+func newMoney(amount int64, currency string) (*money, error) {
+	if currency == "" {
+		return nil, stderrors.New("currency is empty")
+	}
+	return &money{amount: amount, currency: currency}, nil
+}
+
+func init() {
+	if err := RegisterConstructor("money", newMoney); err != nil {
+		panic(err)
+	}
+	registerResultMap(&parser.ResultMap{
+		Id: "test.moneyMap",
+		Constructor: &parser.Constructor{
+			Name: "money",
+			Args: []parser.ConstructorArg{{Column: "amount"}, {Column: "currency"}},
+		},
+		Results: []parser.ResultMapping{{Property: "note", Column: "note"}},
+	})
+}
+
+func TestConstructor(t *testing.T) {
+	ts := newTestSession([]string{"amount", "currency", "note"},
+		[]interface{}{int64(100), []byte("CNY"), "a"},
+		[]interface{}{"200", "USD", nil})
+	ret, _ := ts.Query(context.Background(), "")
+	var ms []*money
+	n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &ms, ret, "test.moneyMap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || ms[0].amount != 100 || ms[0].currency != "CNY" || ms[0].Note != "a" || ms[1].amount != 200 || ms[1].currency != "USD" {
+		t.Fatalf("unexpected result %+v %+v", ms[0], ms[1])
+	}
+
+	ret, _ = ts.Query(context.Background(), "")
+	var m money
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &m, ret, "test.moneyMap"); err != nil {
+		t.Fatal(err)
+	}
+	if m.amount != 100 || m.Note != "a" {
+		t.Fatalf("unexpected result %+v", m)
+	}
+
+	ret, _ = newTestSession([]string{"amount", "currency"}, []interface{}{int64(1), nil}).Query(context.Background(), "")
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &ms, ret, "test.moneyMap"); err == nil || err.Error() != "currency is empty" {
+		t.Fatal("expect constructor error but get ", err)
+	}
+}
+
+func TestConstructorTypeHandler(t *testing.T) {
+	typehandler.RegisterName("test.cents", typehandler.Func(func(v int64) (interface{}, error) {
+		return fmt.Sprintf("%d.%02d", v/100, v%100), nil
+	}, func(src interface{}) (int64, error) {
+		f, err := strconv.ParseFloat(fmt.Sprintf("%s", src), 64)
+		return int64(f*100 + 0.5), err
+	}))
+	registerResultMap(&parser.ResultMap{
+		Id: "test.centsMap",
+		Constructor: &parser.Constructor{
+			Name: "money",
+			Args: []parser.ConstructorArg{{Column: "amount", TypeHandler: "test.cents"}, {Column: "currency"}},
+		},
+	})
+	ret, _ := newTestSession([]string{"amount", "currency"},
+		[]interface{}{[]byte("1.50"), "CNY"}).Query(context.Background(), "")
+	var m money
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &m, ret, "test.centsMap"); err != nil {
+		t.Fatal(err)
+	}
+	if m.amount != 150 || m.currency != "CNY" {
+		t.Fatalf("unexpected result %+v", m)
+	}
+
+	ret, _ = newTestSession([]string{"amount", "currency"},
+		[]interface{}{"abc", "CNY"}).Query(context.Background(), "")
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &m, ret, "test.centsMap"); err == nil {
+		t.Fatal("expect type handler error")
+	}
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	for _, fn := range []interface{}{
+		nil,
+		1,
+		func() {},
+		func() (int, int) { return 0, 0 },
+		func(args ...int) int { return 0 },
+	} {
+		if err := RegisterConstructor("invalid", fn); err != errors.ConstructorInvalid {
+			t.Fatalf("expect ConstructorInvalid for %T but get %v", fn, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/reflection"
+	"reflect"
+)
+
+type CursorRunner interface {
+	// Param 参数，规则同Runner.Param，包含Page时只读取该页的数据
+	Param(params ...interface{}) CursorRunner
+	// Context 设置Context，Context取消时游标自动关闭
+	Context(ctx context.Context) CursorRunner
+	// FetchSize 设置读取行数提示，未设置时使用语句的fetchSize属性
+	// 注意：database/sql没有设置fetchSize的接口，该值只作为提示通过Cursor.FetchSize返回，不会传递给驱动
+	FetchSize(size int) CursorRunner
+	// Cursor 打开游标，使用完毕后必须调用Close
+	Cursor() (*Cursor, error)
+	// Each 逐行将结果解析到bean中并回调fn，bean在每次回调之间复用
+	// fn返回error或者Context取消时提前结束并关闭游标
+	Each(bean interface{}, fn func() error) error
+}
+
+type cursorRunner struct {
+	runner    *SelectRunner
+	fetchSize int
+}
+
+// Cursor 查询结果游标，每次只解析一行数据
+type Cursor struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	result    resultset.QueryResult
+	fetchSize int
+	resultMap string
+	sess      *Session
+	err       error
+	closed    bool
+}
+
+// SelectCursor 使用游标方式查询，避免一次性将全部结果加载到内存
+func (s *Session) SelectCursor(sql string) CursorRunner {
+	return &cursorRunner{
+		runner: s.createSelect(sql, s.findSqlParser(sql)).(*SelectRunner),
+	}
+}
+
+func (r *cursorRunner) Param(params ...interface{}) CursorRunner {
+	r.runner.Param(params...)
+	return r
+}
+
+func (r *cursorRunner) Context(ctx context.Context) CursorRunner {
+	r.runner.Context(ctx)
+	return r
+}
+
+func (r *cursorRunner) FetchSize(size int) CursorRunner {
+	r.fetchSize = size
+	return r
+}
+
+func (r *cursorRunner) Cursor() (*Cursor, error) {
+	sr := r.runner
+	if err := sr.ready(); err != nil {
+		return nil, err
+	}
+
+	md := sr.metadata
+	// 参数中包含分页参数时只读取该页的数据，不执行count语句
+	if sr.page != nil {
+		page := *sr.page
+		if page.Num < 1 || page.Size <= 0 {
+			return nil, sr.execError(errors.PageInvalid, md)
+		}
+		md = sr.pagedMetadata(page)
+	}
+
+	// 超时设置对游标的整个读取过程有效
+	ctx, cancel := sr.sess.statementContext(sr.ctx, md)
+	var ret resultset.Result
+	err := sr.invokeContext(ctx, md, nil, func(ctx context.Context, inv *Invocation) error {
+		var err error
+		ret, err = sr.session.Query(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
+		if err != nil {
+			sr.logger.Warnln(err)
+		}
+		return err
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if ret == nil {
+		cancel()
+		return nil, sr.execError(errors.RunnerNotReady, md)
+	}
+
+	size := r.fetchSize
+	if size <= 0 {
+		size = md.Attributes.FetchSize
+	}
+	c := newCursor(ctx, cancel, ret, size)
+	c.resultMap = md.Attributes.ResultMap
+	c.sess = sr.sess
+	return c, nil
+}
+
+func (r *cursorRunner) Each(bean interface{}, fn func() error) error {
+	if reflection.IsNil(bean) {
+		return errors.ResultPointerIsNil
+	}
+	c, err := r.Cursor()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	for c.Next() {
+		if err := c.Scan(bean); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return c.Err()
+}
+
+func newCursor(ctx context.Context, cancel context.CancelFunc, result resultset.QueryResult, fetchSize int) *Cursor {
+	return &Cursor{
+		ctx:       ctx,
+		cancel:    cancel,
+		result:    result,
+		fetchSize: fetchSize,
+	}
+}
+
+// Next 移动到下一行，没有数据、Context取消或者游标关闭时返回false并关闭游标
+func (c *Cursor) Next() bool {
+	if c.closed {
+		return false
+	}
+	if err := c.ctx.Err(); err != nil {
+		c.err = timeoutError(c.ctx, err)
+		_ = c.Close()
+		return false
+	}
+	if !c.result.Next() {
+		c.err = timeoutError(c.ctx, c.ctx.Err())
+		if c.err == nil {
+			if r, ok := c.result.(rowsErr); ok {
+				c.err = r.Err()
+			}
+		}
+		_ = c.Close()
+		return false
+	}
+	return true
+}
+
+// Scan 将当前行解析到bean中，bean必须为指针，解析前会被重置为零值
+func (c *Cursor) Scan(bean interface{}) error {
+	if reflection.IsNil(bean) {
+		return errors.ResultPointerIsNil
+	}
+	rv := reflect.ValueOf(bean)
+	if rv.Kind() != reflect.Ptr {
+		return errors.ResultIsnotPointer
+	}
+	if c.closed {
+		return errors.ResultSelectEmptyValue
+	}
+	ev := rv.Elem()
+	ev.Set(reflect.Zero(ev.Type()))
+	_, err := scanRows(c.ctx, c.sess, bean, &currentRow{result: c.result}, c.resultMap)
+	return err
+}
+
+// FetchSize 游标使用的读取行数提示
+func (c *Cursor) FetchSize() int {
+	return c.fetchSize
+}
+
+// Err 游标因Context取消或者读取失败结束时返回对应错误，超时返回ExecutorTimeout
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close 关闭游标，可以重复调用
+func (c *Cursor) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	defer c.cancel()
+	return c.result.Close()
+}
+
+// rowsErr 结果集实现该接口时，游标结束后通过Err获得迭代过程中的错误，如*sql.Rows
+type rowsErr interface {
+	Err() error
+}
+
+// currentRow 只暴露游标当前行，避免mapping在解析时移动游标
+type currentRow struct {
+	result resultset.QueryResult
+	read   bool
+}
+
+func (r *currentRow) Columns() ([]string, error) {
+	return r.result.Columns()
+}
+
+func (r *currentRow) Next() bool {
+	if r.read {
+		return false
+	}
+	r.read = true
+	return true
+}
+
+func (r *currentRow) Scan(dest ...interface{}) error {
+	return r.result.Scan(dest...)
+}
+
+func (r *currentRow) Close() error {
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	columns := []string{"id", "name"}
+	t.Run("each", func(t *testing.T) {
+		ts := newTestSession(columns, []interface{}{int64(1), "a"}, []interface{}{int64(2), nil}, []interface{}{int64(3), "c"})
+		sess := newTestSqlSession(ts, "mysql")
+		var row testRow
+		var names []string
+		err := sess.SelectCursor("SELECT id, name FROM tbl_user").Param().Each(&row, func() error {
+			names = append(names, row.Name)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 3 || names[0] != "a" || names[1] != "" || names[2] != "c" {
+			t.Fatal("expect [a  c] but get ", names)
+		}
+		if ts.closed != 1 {
+			t.Fatal("expect closed once but get ", ts.closed)
+		}
+	})
+
+	t.Run("early exit", func(t *testing.T) {
+		ts := newTestSession(columns, []interface{}{int64(1), "a"}, []interface{}{int64(2), "b"})
+		sess := newTestSqlSession(ts, "mysql")
+		var row testRow
+		stop := errors.New("stop")
+		err := sess.SelectCursor("SELECT id, name FROM tbl_user").Param().Each(&row, func() error {
+			return stop
+		})
+		if err != stop {
+			t.Fatal("expect stop error but get ", err)
+		}
+		if row.Id != 1 || ts.closed != 1 {
+			t.Fatal("expect first row and closed cursor but get ", row, ts.closed)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ts := newTestSession(columns, []interface{}{int64(1), "a"}, []interface{}{int64(2), "b"})
+		sess := newTestSqlSession(ts, "mysql")
+		ctx, cancel := context.WithCancel(context.Background())
+		c, err := sess.SelectCursor("SELECT id, name FROM tbl_user").Context(ctx).FetchSize(100).Param().Cursor()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.FetchSize() != 100 {
+			t.Fatal("expect fetch size 100 but get ", c.FetchSize())
+		}
+		var row testRow
+		if !c.Next() {
+			t.Fatal("expect row")
+		}
+		if err := c.Scan(&row); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		if c.Next() {
+			t.Fatal("expect cursor stopped")
+		}
+		if c.Err() != context.Canceled || ts.closed != 1 {
+			t.Fatal("expect canceled and closed but get ", c.Err(), ts.closed)
+		}
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		ts := newTestSession(columns, []interface{}{int64(1), "a"})
+		ts.rowsErr = errors.New("broken connection")
+		sess := newTestSqlSession(ts, "mysql")
+		var row testRow
+		err := sess.SelectCursor("SELECT id, name FROM tbl_user").Param().Each(&row, func() error {
+			return nil
+		})
+		if err != ts.rowsErr {
+			t.Fatal("expect rows error but get ", err)
+		}
+	})
+}
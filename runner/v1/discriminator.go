@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/manager"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+)
+
+// ResultFactory 创建结果对象，返回结构体指针
+type ResultFactory func() interface{}
+
+// RegisterResultType 在全局解析器注册表中注册resultMap中type名称对应的结果类型
+// discriminator的结果为interface时根据case的类型名称创建对象
+func RegisterResultType(name string, factory ResultFactory) error {
+	return registerResultType(manager.GetGlobalParserRegistry(), name, factory)
+}
+
+// RegisterResultType 在SessionManager使用的解析器注册表中注册结果类型
+func (sm *SessionManager) RegisterResultType(name string, factory ResultFactory) error {
+	return registerResultType(sm.registry, name, factory)
+}
+
+func registerResultType(registry parser.Registry, name string, factory ResultFactory) error {
+	r, ok := registry.(parser.ResultMapRegistry)
+	if !ok {
+		return errors.ResultMapRegistryNotSupport
+	}
+	r.AddResultType(name, factory)
+	return nil
+}
+
+func (s *Session) findResultType(name string) (ResultFactory, bool) {
+	if r, ok := s.registry.(parser.ResultMapRegistry); ok {
+		return r.FindResultType(name)
+	}
+	return nil, false
+}
+
+// discriminable 结果为interface、结构体或者结构体指针时可以使用鉴别器
+func discriminable(et reflect.Type) bool {
+	switch et.Kind() {
+	case reflect.Interface, reflect.Struct:
+		return true
+	case reflect.Ptr:
+		return et.Elem().Kind() == reflect.Struct
+	}
+	return false
+}
+
+// discriminatorCase 编译后的case，mapper为外层映射与case映射合并后的结果
+type discriminatorCase struct {
+	typeName string
+	// factory 注册的结果类型，未注册时为nil
+	factory ResultFactory
+	mapper  *rowMapper
+}
+
+// scanDiscriminated 根据鉴别列的值选择映射，et为interface时使用注册的结果类型创建对象
+// 鉴别器只支持简单映射，不支持association以及collection
+func scanDiscriminated(sess *Session, rv reflect.Value, isSlice bool, et reflect.Type, result resultset.QueryResult, rm *parser.ResultMap) (int64, error) {
+	columns, err := result.Columns()
+	if err != nil {
+		return 0, err
+	}
+	column := -1
+	for i, c := range columns {
+		if strings.EqualFold(c, rm.Discriminator.Column) {
+			column = i
+			break
+		}
+	}
+	if column < 0 {
+		xlog.Warnf("result map %s discriminator column %s not found\n", rm.Id, rm.Discriminator.Column)
+		return 0, errors.ResultNameNotFound
+	}
+	cases := map[string]*discriminatorCase{}
+	for _, c := range rm.Discriminator.Cases {
+		dc, err := compileCase(sess, rm, c, columns)
+		if err != nil {
+			return 0, err
+		}
+		cases[c.Value] = dc
+	}
+	defaultCase := &discriminatorCase{typeName: rm.Type, mapper: newRowMapper(rm, columns)}
+	defaultCase.factory, _ = sess.findResultType(rm.Type)
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var count int64
+	for result.Next() {
+		if err := result.Scan(dest...); err != nil {
+			return count, err
+		}
+		value, err := discriminatorValue(rm.Discriminator, values[column])
+		if err != nil {
+			return count, err
+		}
+		dc, ok := cases[value]
+		if !ok {
+			dc = defaultCase
+		}
+		ev, err := dc.newValue(et)
+		if err != nil {
+			return count, err
+		}
+		if err := dc.mapper.mapRow(reflect.Indirect(ev), values); err != nil {
+			return count, err
+		}
+		if !ev.Type().AssignableTo(et) {
+			ev = ev.Elem()
+		}
+		count++
+		if !isSlice {
+			rv.Set(ev)
+			break
+		}
+		rv.Set(reflect.Append(rv, ev))
+	}
+	return count, nil
+}
+
+func compileCase(sess *Session, rm *parser.ResultMap, c parser.DiscriminatorCase, columns []string) (*discriminatorCase, error) {
+	caseMap := c.ResultMap
+	if caseMap == nil {
+		var ok bool
+		if caseMap, ok = sess.findResultMap(c.ResultMapId); !ok {
+			xlog.Warnf("result map %s not found\n", c.ResultMapId)
+			return nil, errors.ResultMapNotFound
+		}
+	}
+	merged := &parser.ResultMap{
+		Id:          caseMap.Id,
+		AutoMapping: rm.AutoMapping && caseMap.AutoMapping,
+		Results:     append(append([]parser.ResultMapping(nil), rm.Results...), caseMap.Results...),
+	}
+	typeName := c.ResultType
+	if typeName == "" {
+		typeName = caseMap.Type
+	}
+	if typeName == "" {
+		typeName = rm.Type
+	}
+	factory, _ := sess.findResultType(typeName)
+	return &discriminatorCase{
+		typeName: typeName,
+		factory:  factory,
+		mapper:   newRowMapper(merged, columns),
+	}, nil
+}
+
+// newValue 创建结果对象，返回结构体指针
+func (c *discriminatorCase) newValue(et reflect.Type) (reflect.Value, error) {
+	if et.Kind() != reflect.Interface {
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+		return reflect.New(et), nil
+	}
+	if c.factory == nil {
+		xlog.Warnf("result type %s is not registered\n", c.typeName)
+		return reflect.Value{}, errors.ResultMapInvalid
+	}
+	v := reflect.ValueOf(c.factory())
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		xlog.Warnf("result type %s factory must return a struct pointer, got %s\n", c.typeName, v.Type())
+		return reflect.Value{}, errors.ResultMapInvalid
+	}
+	if !v.Type().Implements(et) && !v.Elem().Type().Implements(et) {
+		xlog.Warnf("result type %s does not implement %s\n", c.typeName, et)
+		return reflect.Value{}, errors.ResultMapInvalid
+	}
+	return v, nil
+}
+
+// discriminatorValue 将列值转换为与case比较的字符串
+// 与列映射相同，优先使用鉴别器指定的处理器，其次使用按照string类型注册的处理器
+func discriminatorValue(d *parser.Discriminator, v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	var s string
+	if ok, err := typehandler.Scan(v, reflect.ValueOf(&s).Elem(), d.TypeHandler, d.JdbcType); ok || err != nil {
+		if err != nil {
+			xlog.Warnf("discriminator column %s type handler failed: %v\n", d.Column, err)
+		}
+		return s, err
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b), nil
+	}
+	return fmt.Sprint(v), nil
+}
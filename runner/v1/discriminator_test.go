@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"testing"
+)
+
+type payment interface {
+	PaymentId() int64
+}
+
+type cardPayment struct {
+	Id     int64
+	CardNo string
+}
+
+func (p *cardPayment) PaymentId() int64 { return p.Id }
+
+type cashPayment struct {
+	Id       int64
+	Currency string
+}
+
+func (p cashPayment) PaymentId() int64 { return p.Id }
+
+type paymentRow struct {
+	Id       int64
+	Type     string `column:"type"`
+	CardNo   string
+	Currency string
+}
+
+func init() {
+	RegisterResultType("CardPayment", func() interface{} { return &cardPayment{} })
+	RegisterResultType("CashPayment", func() interface{} { return &cashPayment{} })
+	registerResultMap(&parser.ResultMap{
+		Id:   "test.cashMap",
+		Type: "CashPayment",
+		Results: []parser.ResultMapping{
+			{Property: "currency", Column: "extra"},
+		},
+	})
+	registerResultMap(&parser.ResultMap{
+		Id:          "test.paymentMap",
+		AutoMapping: true,
+		Results: []parser.ResultMapping{
+			{Property: "id", Column: "id", Id: true},
+		},
+		Discriminator: &parser.Discriminator{
+			Column: "type",
+			Cases: []parser.DiscriminatorCase{
+				{Value: "card", ResultType: "CardPayment", ResultMap: &parser.ResultMap{
+					AutoMapping: true,
+					Results:     []parser.ResultMapping{{Property: "cardNo", Column: "extra"}},
+				}},
+				{Value: "cash", ResultMapId: "test.cashMap"},
+			},
+		},
+	})
+}
+
+func paymentSession() *testSession {
+	return newTestSession([]string{"id", "type", "extra"},
+		[]interface{}{int64(1), "card", "6222"},
+		[]interface{}{int64(2), []byte("cash"), "CNY"},
+		[]interface{}{int64(3), "card", "6228"})
+}
+
+func TestDiscriminator(t *testing.T) {
+	ret, _ := paymentSession().Query(context.Background(), "")
+	var payments []payment
+	n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &payments, ret, "test.paymentMap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatal("expect 3 rows but get ", n)
+	}
+	if p, ok := payments[0].(*cardPayment); !ok || p.Id != 1 || p.CardNo != "6222" {
+		t.Fatalf("unexpected payment %#v", payments[0])
+	}
+	if p, ok := payments[1].(*cashPayment); !ok || p.Id != 2 || p.Currency != "CNY" {
+		t.Fatalf("unexpected payment %#v", payments[1])
+	}
+	if payments[2].PaymentId() != 3 {
+		t.Fatalf("unexpected payment %#v", payments[2])
+	}
+
+	// 结果为结构体时所有case映射到同一类型
+	ret, _ = paymentSession().Query(context.Background(), "")
+	var rows []paymentRow
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &rows, ret, "test.paymentMap"); err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].CardNo != "6222" || rows[0].Type != "card" || rows[1].CardNo != "" || rows[1].Currency != "CNY" {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+}
+
+func TestDiscriminatorUnregisteredType(t *testing.T) {
+	registerResultMap(&parser.ResultMap{
+		Id:      "test.unknownPaymentMap",
+		Results: []parser.ResultMapping{{Property: "id", Column: "id"}},
+		Discriminator: &parser.Discriminator{
+			Column: "type",
+			Cases:  []parser.DiscriminatorCase{{Value: "card", ResultType: "UnknownPayment", ResultMap: &parser.ResultMap{}}},
+		},
+	})
+	ret, _ := paymentSession().Query(context.Background(), "")
+	var payments []payment
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &payments, ret, "test.unknownPaymentMap"); err != errors.ResultMapInvalid {
+		t.Fatal("expect ResultMapInvalid but get ", err)
+	}
+}
+
+func TestDiscriminatorTypeHandler(t *testing.T) {
+	types := map[string]string{"1": "card", "2": "cash"}
+	typehandler.RegisterName("test.paymentType", typehandler.Func(func(v string) (interface{}, error) {
+		return v, nil
+	}, func(src interface{}) (string, error) {
+		if v, ok := types[fmt.Sprint(src)]; ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("unknown payment type %v", src)
+	}))
+	registerResultMap(&parser.ResultMap{
+		Id:      "test.paymentCodeMap",
+		Results: []parser.ResultMapping{{Property: "id", Column: "id"}},
+		Discriminator: &parser.Discriminator{
+			Column:      "type",
+			TypeHandler: "test.paymentType",
+			Cases: []parser.DiscriminatorCase{
+				{Value: "card", ResultType: "CardPayment", ResultMap: &parser.ResultMap{
+					Results: []parser.ResultMapping{{Property: "cardNo", Column: "extra"}},
+				}},
+				{Value: "cash", ResultMapId: "test.cashMap"},
+			},
+		},
+	})
+	ret, _ := newTestSession([]string{"id", "type", "extra"},
+		[]interface{}{int64(1), int64(1), "6222"},
+		[]interface{}{int64(2), int64(2), "CNY"}).Query(context.Background(), "")
+	var payments []payment
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &payments, ret, "test.paymentCodeMap"); err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := payments[0].(*cardPayment); !ok || p.CardNo != "6222" {
+		t.Fatalf("unexpected payment %#v", payments[0])
+	}
+	if p, ok := payments[1].(*cashPayment); !ok || p.Currency != "CNY" {
+		t.Fatalf("unexpected payment %#v", payments[1])
+	}
+
+	ret, _ = newTestSession([]string{"id", "type", "extra"},
+		[]interface{}{int64(1), int64(3), "6222"}).Query(context.Background(), "")
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &payments, ret, "test.paymentCodeMap"); err == nil {
+		t.Fatal("expect type handler error")
+	}
+}
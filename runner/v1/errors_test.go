@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"github.com/xfali/gobatis/v2/errors"
+	"testing"
+)
+
+func TestResultError(t *testing.T) {
+	conn := &testConnection{}
+	sm := newTestSessionManager(conn, "mysql")
+	sess := sm.NewSession()
+
+	sql := "SELECT id, name FROM tbl_user WHERE id = #{notExist}"
+	var rows []testRow
+	err := sess.Select(sql).Param(1).Result(&rows)
+	if !errors.Is(err, errors.ParseSqlParamError) {
+		t.Fatal("expect ParseSqlParamError but get ", err)
+	}
+	var e *errors.Error
+	if !errors.As(err, &e) || e.SqlId != sql || e.Driver != "mysql" || e.Sql != "" {
+		t.Fatalf("unexpected error %+v", e)
+	}
+
+	r := sess.Select("SELECT id, name FROM tbl_user").Param()
+	if err := r.Result(nil); !errors.Is(err, errors.ResultPointerIsNil) {
+		t.Fatal("expect ResultPointerIsNil but get ", err)
+	}
+	if err := r.Result(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	err = (&SelectRunner{BaseRunner: BaseRunner{sqlId: "test.select", logger: sess.logger}}).Result(&rows)
+	if !errors.Is(err, errors.RunnerNotReady) || !errors.As(err, &e) || e.SqlId != "test.select" {
+		t.Fatal("expect RunnerNotReady but get ", err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+)
+
+// Query 执行查询语句，返回T类型的结果列表
+func Query[T any](ctx context.Context, sess *Session, sqlId string, params ...interface{}) ([]T, error) {
+	var ret []T
+	err := sess.Select(sqlId).Context(ctx).Param(params...).Result(&ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// QueryOne 执行查询语句，返回唯一的一条结果
+// 没有结果时返回ResultSelectEmptyValue，多于一条时返回ResultTooManyRows
+func QueryOne[T any](ctx context.Context, sess *Session, sqlId string, params ...interface{}) (T, error) {
+	var zero T
+	ret, err := Query[T](ctx, sess, sqlId, params...)
+	if err != nil {
+		return zero, err
+	}
+	switch len(ret) {
+	case 0:
+		return zero, errors.ResultSelectEmptyValue
+	case 1:
+		return ret[0], nil
+	}
+	return zero, errors.ResultTooManyRows
+}
+
+// Exec 执行update、delete等语句，返回影响的行数
+func Exec(ctx context.Context, sess *Session, sqlId string, params ...interface{}) (int64, error) {
+	r := sess.Exec(sqlId).Context(ctx).Param(params...)
+	if err := r.Result(nil); err != nil {
+		return 0, err
+	}
+	if ra, ok := r.(RowsAffecter); ok {
+		return ra.RowsAffected(), nil
+	}
+	return 0, nil
+}
+
+// Insert 执行insert语句，返回最后插入的自增id
+// 数据库不支持获取自增id且语句未使用useGeneratedKeys时返回0
+func Insert(ctx context.Context, sess *Session, sqlId string, params ...interface{}) (int64, error) {
+	r := sess.Insert(sqlId).Context(ctx).Param(params...)
+	if err := r.Result(nil); err != nil {
+		return 0, err
+	}
+	return r.LastInsertId(), nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/lean/resultset"
+	"testing"
+)
+
+// noInsertIdSession 模拟不支持LastInsertId的驱动，如postgresql
+type noInsertIdSession struct {
+	*testSession
+}
+
+type noInsertIdResult struct {
+	*testResult
+}
+
+func (r *noInsertIdResult) LastInsertId() (int64, error) {
+	return 0, stderrors.New("LastInsertId is not supported by this driver")
+}
+
+func (s *noInsertIdSession) Execute(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	ret, err := s.testSession.Execute(ctx, stmt, params...)
+	return &noInsertIdResult{testResult: ret.(*testResult)}, err
+}
+
+func TestGenericHelpers(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"}, []interface{}{int64(2), "b"})
+	sess := newTestSqlSession(ts, "mysql")
+
+	rows, err := Query[testRow](ctx, sess, "SELECT id, name FROM tbl_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1].Name != "b" {
+		t.Fatal("unexpected rows ", rows)
+	}
+
+	if _, err := QueryOne[testRow](ctx, sess, "SELECT id, name FROM tbl_user"); err != errors.ResultTooManyRows {
+		t.Fatal("expect too many rows but get ", err)
+	}
+	empty := newTestSqlSession(newTestSession([]string{"id", "name"}), "mysql")
+	if _, err := QueryOne[testRow](ctx, empty, "SELECT id, name FROM tbl_user"); err != errors.ResultSelectEmptyValue {
+		t.Fatal("expect empty value but get ", err)
+	}
+	one := newTestSqlSession(newTestSession([]string{"id", "name"}, []interface{}{int64(3), "c"}), "mysql")
+	row, err := QueryOne[testRow](ctx, one, "SELECT id, name FROM tbl_user WHERE id = #{0}", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.Id != 3 || row.Name != "c" {
+		t.Fatal("unexpected row ", row)
+	}
+
+	n, err := Exec(ctx, sess, "UPDATE tbl_user SET name = #{0}", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatal("expect 1 but get ", n)
+	}
+
+	id, err := Insert(ctx, newTestSqlSession(&insertSession{testSession: newTestSession(nil), lastId: 42}, "mysql"),
+		"INSERT INTO tbl_user(name) VALUES(#{0})", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Fatal("expect 42 but get ", id)
+	}
+
+	for _, driver := range []string{"postgres", "oci8"} {
+		id, err = Insert(ctx, newTestSqlSession(&noInsertIdSession{newTestSession(nil)}, driver),
+			"INSERT INTO tbl_user(name) VALUES(#{0})", "x")
+		if err != nil || id != 0 {
+			t.Fatal(driver, " expect 0 without error but get ", id, err)
+		}
+	}
+}
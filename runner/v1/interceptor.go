@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"time"
+)
+
+// Invocation 一次语句执行的信息，在拦截器之间传递
+type Invocation struct {
+	// Session 执行语句的session
+	Session *Session
+	// SqlId 语句id，直接使用sql语句执行时为sql语句本身
+	SqlId string
+	// Action 期望的语句类型：select、insert、update、delete，Exec时为空
+	Action string
+	// Driver 驱动名称
+	Driver string
+	// Params 调用Param时传入的参数
+	Params []interface{}
+	// Metadata 解析后的语句，拦截器可以修改或者替换，执行时使用该值
+	Metadata *parser.Metadata
+	// Bean 调用Result时传入的结果对象，游标以及批量执行时为nil
+	Bean interface{}
+	// RowsAffected 写语句影响的行数，查询语句为读取的行数，执行后有效
+	RowsAffected int64
+	// LastInsertId insert语句最后插入的自增id，执行后有效
+	LastInsertId int64
+	// RenderTime 生成sql语句的耗时
+	RenderTime time.Duration
+}
+
+// Invoker 执行语句
+type Invoker func(ctx context.Context, inv *Invocation) error
+
+// Interceptor 语句执行拦截器
+// 在调用next之前可以检查或者改写inv.Metadata，调用next之后可以处理执行结果
+// 不调用next则语句不会被执行
+type Interceptor interface {
+	Intercept(ctx context.Context, inv *Invocation, next Invoker) error
+}
+
+type InterceptorFunc func(ctx context.Context, inv *Invocation, next Invoker) error
+
+func (f InterceptorFunc) Intercept(ctx context.Context, inv *Invocation, next Invoker) error {
+	return f(ctx, inv, next)
+}
+
+// AddInterceptor 添加拦截器，只对之后创建的session生效，按照添加顺序由外向内执行
+func (sm *SessionManager) AddInterceptor(interceptors ...Interceptor) {
+	sm.interceptors = append(sm.interceptors, interceptors...)
+}
+
+// AddInterceptor 添加只对当前session生效的拦截器，在SessionManager的拦截器之后执行
+func (s *Session) AddInterceptor(interceptors ...Interceptor) {
+	s.interceptors = append(s.interceptors, interceptors...)
+}
+
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, inv *Invocation) error {
+			return interceptor.Intercept(ctx, inv, next)
+		}
+	}
+	return invoker
+}
+
+// wrapInvoker 为invoker加上拦截器链、慢查询日志、统计以及追踪
+func (s *Session) wrapInvoker(invoker Invoker) Invoker {
+	return s.traceInvoker(s.statsInvoker(s.slowQueryInvoker(chainInterceptors(s.interceptors, invoker))))
+}
+
+func (baseRunner *BaseRunner) newInvocation(md *parser.Metadata, bean interface{}) *Invocation {
+	return &Invocation{
+		Session:    baseRunner.sess,
+		SqlId:      baseRunner.sqlId,
+		Action:     baseRunner.action,
+		Driver:     baseRunner.driver,
+		Params:     baseRunner.params,
+		Metadata:   md,
+		Bean:       bean,
+		RenderTime: baseRunner.renderTime,
+	}
+}
+
+// invoke 经过拦截器链执行语句，超时设置只在执行期间有效
+func (baseRunner *BaseRunner) invoke(bean interface{}, invoker Invoker) error {
+	return baseRunner.invokeWith(baseRunner.metadata, bean, invoker)
+}
+
+// invokeWith 使用指定的语句执行，用于分页等需要改写语句的场景
+func (baseRunner *BaseRunner) invokeWith(md *parser.Metadata, bean interface{}, invoker Invoker) error {
+	ctx, cancel := baseRunner.sess.statementContext(baseRunner.ctx, md)
+	defer cancel()
+	return baseRunner.invokeContext(ctx, md, bean, invoker)
+}
+
+func (baseRunner *BaseRunner) invokeContext(ctx context.Context, md *parser.Metadata, bean interface{}, invoker Invoker) error {
+	inv := baseRunner.newInvocation(md, bean)
+	if baseRunner.sess != nil {
+		invoker = baseRunner.sess.wrapInvoker(invoker)
+	}
+	err := invoker(ctx, inv)
+	baseRunner.rowsAffected = inv.RowsAffected
+	baseRunner.sess.flushCache(inv.Metadata)
+	baseRunner.sess.clearLocalCacheAfter(inv)
+	return baseRunner.execError(timeoutError(ctx, err), inv.Metadata)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestInterceptor(t *testing.T) {
+	conn := &testConnection{}
+	sm := newTestSessionManager(conn, "mysql")
+	var order []string
+	sm.AddInterceptor(InterceptorFunc(func(ctx context.Context, inv *Invocation, next Invoker) error {
+		order = append(order, "manager")
+		md := *inv.Metadata
+		md.PrepareSql = md.PrepareSql + " AND tenant_id = 1"
+		inv.Metadata = &md
+		err := next(ctx, inv)
+		order = append(order, "manager done")
+		return err
+	}))
+	sess := sm.NewSession()
+	sess.AddInterceptor(InterceptorFunc(func(ctx context.Context, inv *Invocation, next Invoker) error {
+		order = append(order, "session "+inv.Action)
+		err := next(ctx, inv)
+		if inv.RowsAffected != 1 {
+			t.Fatal("expect 1 row affected but get ", inv.RowsAffected)
+		}
+		return err
+	}))
+
+	var count int64
+	err := sess.Update("UPDATE tbl_user SET name = #{0} WHERE id = #{1}").Param("x", 1).Result(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"manager", "session update", "manager done"}
+	if !reflect.DeepEqual(order, expect) {
+		t.Fatal("expect ", expect, " but get ", order)
+	}
+	executed := conn.sessions[0].executed
+	if executed[0] != "UPDATE tbl_user SET name = ? WHERE id = ? AND tenant_id = 1" {
+		t.Fatal("expect rewritten sql but get ", executed[0])
+	}
+	if count != 1 {
+		t.Fatal("expect count 1 but get ", count)
+	}
+
+	sm.NewSession().Select("SELECT * FROM tbl_user").Param()
+	if len(sm.interceptors) != 1 {
+		t.Fatal("expect session interceptors not shared with manager")
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/dialect"
+	"github.com/xfali/lean/mapping"
+	"github.com/xfali/reflection"
+	"reflect"
+	"strings"
+)
+
+// insertReturning 使用RETURNING或者OUTPUT子句执行insert并读取生成的主键
+// 返回false表示语句无法改写，需要按普通insert执行
+func (r *InsertRunner) insertReturning(ctx context.Context, inv *Invocation, d dialect.Dialect) (bool, error) {
+	attrs := inv.Metadata.Attributes
+	columns := attrs.KeyColumn
+	if len(columns) == 0 {
+		columns = attrs.KeyProperty
+	}
+	sql, ok := d.Returning(inv.Metadata.PrepareSql, columns)
+	if !ok {
+		r.logger.Warnf("Cannot add generated keys clause to sql: %s\n", inv.Metadata.PrepareSql)
+		return false, nil
+	}
+	md := *inv.Metadata
+	md.PrepareSql = sql
+	inv.Metadata = &md
+
+	ret, err := r.session.Query(ctx, md.PrepareSql, md.Params...)
+	if err != nil {
+		r.logger.Warnln(err)
+		return true, err
+	}
+	defer ret.Close()
+
+	targets := keyTargets(inv.Params)
+	var n int
+	for ret.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := ret.Scan(dest...); err != nil {
+			r.logger.Warnln(err)
+			return true, err
+		}
+		if n == 0 {
+			r.lastId = toInt64(values[0])
+		}
+		if n < len(targets) {
+			for i, property := range attrs.KeyProperty {
+				if i < len(values) && !setKeyProperty(targets[n], property, values[i]) {
+					r.logger.Warnf("Cannot set generated key %s to %s\n", property, targets[n].Type())
+				}
+			}
+		}
+		n++
+	}
+	// 结果集提前结束时后续的主键无法回写，不能按成功返回
+	if re, ok := ret.(rowsErr); ok {
+		if err := re.Err(); err != nil {
+			r.logger.Warnln(err)
+			return true, err
+		}
+	}
+	inv.LastInsertId = r.lastId
+	inv.RowsAffected = int64(n)
+	if reflection.CanSet(inv.Bean) {
+		err = reflection.SetValueInterface(inv.Bean, r.lastId)
+	}
+	return true, err
+}
+
+// setInsertIds 将LastInsertId推算出的主键写回参数
+func (r *InsertRunner) setInsertIds(inv *Invocation, mode dialect.KeyMode) {
+	targets := keyTargets(inv.Params)
+	if len(targets) == 0 {
+		return
+	}
+	first := r.lastId
+	if mode == dialect.KeyModeLastInsertId {
+		first = r.lastId - int64(len(targets)-1)
+	}
+	property := inv.Metadata.Attributes.KeyProperty[0]
+	for i, target := range targets {
+		if !setKeyProperty(target, property, first+int64(i)) {
+			r.logger.Warnf("Cannot set generated key %s to %s\n", property, target.Type())
+		}
+	}
+}
+
+// keyTargets 收集参数中可以写回主键的struct，slice参数展开为每个元素
+func keyTargets(params []interface{}) []reflect.Value {
+	var ret []reflect.Value
+	for _, p := range params {
+		rv := reflect.ValueOf(p)
+		if rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+		switch rv.Kind() {
+		case reflect.Struct:
+			if rv.CanSet() {
+				ret = append(ret, rv)
+			}
+		case reflect.Slice:
+			for i := 0; i < rv.Len(); i++ {
+				ev := rv.Index(i)
+				if ev.Kind() == reflect.Ptr {
+					ev = ev.Elem()
+				}
+				if ev.Kind() == reflect.Struct && ev.CanSet() {
+					ret = append(ret, ev)
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// setKeyProperty 按照column tag或者字段名查找字段并写入主键
+func setKeyProperty(target reflect.Value, property string, value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	field := keyField(target, property)
+	if !field.IsValid() || !field.CanSet() {
+		return false
+	}
+	return reflection.SetValue(field, reflect.ValueOf(value))
+}
+
+func keyField(target reflect.Value, property string) reflect.Value {
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get(mapping.FieldAliasTagName) == property {
+			return target.Field(i)
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(t.Field(i).Name, property) {
+			return target.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func toInt64(v interface{}) int64 {
+	var ret int64
+	if v != nil {
+		reflection.SetValue(reflect.ValueOf(&ret).Elem(), reflect.ValueOf(v))
+	}
+	return ret
+}
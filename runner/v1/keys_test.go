@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"testing"
+)
+
+type insertResult struct {
+	*testResult
+	lastId int64
+}
+
+func (r *insertResult) LastInsertId() (int64, error) {
+	return r.lastId, nil
+}
+
+// insertSession Execute返回固定的LastInsertId
+type insertSession struct {
+	*testSession
+	lastId int64
+}
+
+func (s *insertSession) Execute(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	ret, err := s.testSession.Execute(ctx, stmt, params...)
+	return &insertResult{testResult: ret.(*testResult), lastId: s.lastId}, err
+}
+
+func newKeysRunner(t *testing.T, sess *Session, sql string) Runner {
+	p, err := sess.ParserFactory(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sess.createInsert(sql, &attrParser{Parser: p, attrs: parser.Attributes{
+		UseGeneratedKeys: true,
+		KeyProperty:      []string{"id"},
+	}})
+}
+
+func TestGeneratedKeys(t *testing.T) {
+	sql := "INSERT INTO tbl_user(name) VALUES ('a'), ('b')"
+
+	t.Run("returning", func(t *testing.T) {
+		ts := newTestSession([]string{"id"}, []interface{}{int64(10)}, []interface{}{int64(11)})
+		sess := newTestSqlSession(ts, "postgres")
+		users := []*testRow{{Name: "a"}, {Name: "b"}}
+		var id int64
+		if err := newKeysRunner(t, sess, sql).Param(users).Result(&id); err != nil {
+			t.Fatal(err)
+		}
+		if ts.executed[0] != sql+" RETURNING id" {
+			t.Fatal("unexpected sql ", ts.executed[0])
+		}
+		if users[0].Id != 10 || users[1].Id != 11 || id != 10 {
+			t.Fatal("unexpected keys ", users[0].Id, users[1].Id, id)
+		}
+	})
+
+	t.Run("returning rows error", func(t *testing.T) {
+		ts := newTestSession([]string{"id"}, []interface{}{int64(10)})
+		ts.rowsErr = errors.New("broken connection")
+		sess := newTestSqlSession(ts, "postgres")
+		users := []*testRow{{Name: "a"}, {Name: "b"}}
+		var id int64
+		if err := newKeysRunner(t, sess, sql).Param(users).Result(&id); !errors.Is(err, ts.rowsErr) {
+			t.Fatal("expect rows error but get ", err)
+		}
+	})
+
+	t.Run("output", func(t *testing.T) {
+		ts := newTestSession([]string{"id"}, []interface{}{int64(7)})
+		sess := newTestSqlSession(ts, "sqlserver")
+		user := testRow{Name: "a"}
+		var id int64
+		if err := newKeysRunner(t, sess, "INSERT INTO tbl_user(name) VALUES ('a')").Param(&user).Result(&id); err != nil {
+			t.Fatal(err)
+		}
+		if ts.executed[0] != "INSERT INTO tbl_user(name) OUTPUT INSERTED.id VALUES ('a')" {
+			t.Fatal("unexpected sql ", ts.executed[0])
+		}
+		if user.Id != 7 {
+			t.Fatal("expect 7 but get ", user.Id)
+		}
+	})
+
+	t.Run("last insert id", func(t *testing.T) {
+		for driver, expect := range map[string][]int64{
+			"mysql":   {100, 101},
+			"sqlite3": {99, 100},
+		} {
+			sess := newTestSqlSession(&insertSession{testSession: newTestSession(nil), lastId: 100}, driver)
+			users := []testRow{{Name: "a"}, {Name: "b"}}
+			var id int64
+			if err := newKeysRunner(t, sess, sql).Param(users).Result(&id); err != nil {
+				t.Fatal(err)
+			}
+			if users[0].Id != expect[0] || users[1].Id != expect[1] {
+				t.Fatal(driver, " expect ", expect, " but get ", users[0].Id, users[1].Id)
+			}
+		}
+	})
+}
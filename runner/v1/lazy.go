@@ -0,0 +1,219 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Lazy 延迟加载的属性，对应resultMap中fetchType="lazy"的嵌套select
+// 第一次调用Get时使用创建对象的Session执行查询，之后返回缓存的结果，加载失败时下次调用会重新查询
+// 注意：每个对象的延迟属性都会单独执行一次查询，遍历大量对象并访问延迟属性会产生N+1次查询，
+// 这种场景应当使用join以及association/collection映射；Session关闭之后不能再加载
+// Lazy复制后共享加载状态
+type Lazy[T any] struct {
+	state *lazyState[T]
+}
+
+type lazyState[T any] struct {
+	lock   sync.Mutex
+	loader func(dst reflect.Value) error
+	loaded bool
+	value  T
+}
+
+// lazyLoader Lazy[T]实现该接口，用于在解析结果时设置加载函数
+type lazyLoader interface {
+	setLoader(loader func(dst reflect.Value) error)
+	load() error
+}
+
+var lazyLoaderType = reflect.TypeOf((*lazyLoader)(nil)).Elem()
+
+// Get 获得属性值，没有关联数据时返回零值
+func (l *Lazy[T]) Get() (T, error) {
+	if l.state == nil {
+		var zero T
+		return zero, nil
+	}
+	s := l.state
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.loaded && s.loader != nil {
+		var v T
+		if err := s.loader(reflect.ValueOf(&v).Elem()); err != nil {
+			return v, err
+		}
+		s.value, s.loaded, s.loader = v, true, nil
+	}
+	return s.value, nil
+}
+
+// Loaded 是否已经加载，没有关联数据时返回true
+func (l *Lazy[T]) Loaded() bool {
+	if l.state == nil {
+		return true
+	}
+	l.state.lock.Lock()
+	defer l.state.lock.Unlock()
+
+	return l.state.loaded || l.state.loader == nil
+}
+
+// Set 直接设置属性值，之后不再加载
+func (l *Lazy[T]) Set(v T) {
+	l.state = &lazyState[T]{
+		loaded: true,
+		value:  v,
+	}
+}
+
+func (l *Lazy[T]) setLoader(loader func(dst reflect.Value) error) {
+	l.state = &lazyState[T]{
+		loader: loader,
+	}
+}
+
+func (l *Lazy[T]) load() error {
+	_, err := l.Get()
+	return err
+}
+
+// compileSelect 编译嵌套select，属性类型为Lazy[T]时加载结果到T中
+func (m *nestedMap) compileSelect(n parser.NestedMapping, collection bool, prefix string, indexes map[string]int) error {
+	ft, ok := fieldTypeByPath(m.typ, n.Property)
+	if !ok {
+		xlog.Warnf("result map property %s not found in %s\n", n.Property, m.typ)
+		return errors.ResultNameNotFound
+	}
+	f := &nestedField{
+		property:   n.Property,
+		collection: collection,
+		selectId:   n.Select,
+		lazy:       n.Lazy,
+	}
+	if f.lazy && !reflect.PtrTo(ft).Implements(lazyLoaderType) {
+		xlog.Warnf("result map property %s is not Lazy[T], load eagerly\n", n.Property)
+		f.lazy = false
+	}
+	for _, arg := range parseSelectColumns(n.Column) {
+		idx, ok := indexes[strings.ToLower(prefix+arg.column)]
+		if !ok {
+			xlog.Warnf("result map property %s select column %s not found\n", n.Property, prefix+arg.column)
+			return errors.ResultNameNotFound
+		}
+		f.args = append(f.args, selectArg{name: arg.name, index: idx})
+	}
+	if len(f.args) == 0 {
+		xlog.Warnf("result map property %s select %s without column\n", n.Property, n.Select)
+		return errors.ResultMapInvalid
+	}
+	m.nested = append(m.nested, f)
+	return nil
+}
+
+type selectColumn struct {
+	name   string
+	column string
+}
+
+// parseSelectColumns 解析column属性：column或者{name=column,...}
+func parseSelectColumns(column string) []selectColumn {
+	column = strings.TrimSpace(column)
+	if !strings.HasPrefix(column, "{") || !strings.HasSuffix(column, "}") {
+		if column == "" {
+			return nil
+		}
+		return []selectColumn{{column: column}}
+	}
+	var ret []selectColumn
+	for _, kv := range strings.Split(column[1:len(column)-1], ",") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			continue
+		}
+		ret = append(ret, selectColumn{
+			name:   strings.TrimSpace(kv[:i]),
+			column: strings.TrimSpace(kv[i+1:]),
+		})
+	}
+	return ret
+}
+
+// selectParam 嵌套select的参数，列值全部为NULL时返回nil
+func (f *nestedField) selectParam(values []interface{}) interface{} {
+	if len(f.args) == 1 && f.args[0].name == "" {
+		return values[f.args[0].index]
+	}
+	var ret map[string]interface{}
+	for _, arg := range f.args {
+		if values[arg.index] == nil {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]interface{}, len(f.args))
+		}
+		ret[arg.name] = values[arg.index]
+	}
+	if ret == nil {
+		return nil
+	}
+	return ret
+}
+
+// load 执行嵌套select，延迟加载时只设置加载函数
+func (f *nestedField) load(ctx context.Context, sess *Session, field reflect.Value, param interface{}) error {
+	if sess == nil {
+		xlog.Warnf("result map property %s select %s without session\n", f.property, f.selectId)
+		return errors.RunnerNotReady
+	}
+	if l, ok := field.Addr().Interface().(lazyLoader); ok {
+		// 延迟加载时查询的Context已经结束，使用Session的Context
+		l.setLoader(func(dst reflect.Value) error {
+			return selectInto(sess.ctx, sess, f.selectId, param, dst)
+		})
+		if f.lazy {
+			return nil
+		}
+		return l.load()
+	}
+	return selectInto(ctx, sess, f.selectId, param, field)
+}
+
+// selectInto 执行select并将结果写入dst，dst为结构体指针时只在有结果时赋值
+func selectInto(ctx context.Context, sess *Session, sqlId string, param interface{}, dst reflect.Value) error {
+	r := sess.Select(sqlId).Context(ctx).Param(param)
+	if dst.Kind() == reflect.Ptr && dst.Type().Elem().Kind() == reflect.Struct {
+		rows := reflect.New(reflect.SliceOf(dst.Type().Elem()))
+		if err := r.Result(rows.Interface()); err != nil {
+			return err
+		}
+		if rows.Elem().Len() > 0 {
+			dst.Set(rows.Elem().Index(0).Addr())
+		}
+		return nil
+	}
+	return r.Result(dst.Addr().Interface())
+}
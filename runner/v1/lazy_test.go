@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"strings"
+	"testing"
+)
+
+// ownerSession 查询tbl_owner时返回owner数据
+type ownerSession struct {
+	*testSession
+}
+
+func (s *ownerSession) Query(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	if strings.Contains(stmt, "tbl_owner") {
+		s.executed = append(s.executed, stmt)
+		s.params = append(s.params, params)
+		return &testResult{
+			SliceResult: resultset.NewSliceResult([][]interface{}{{params[0], "owner"}}, []string{"id", "name"}, testRowSetter),
+		}, nil
+	}
+	return s.testSession.Query(ctx, stmt, params...)
+}
+
+type lazyOwner struct {
+	Id   int64  `column:"id"`
+	Name string `column:"name"`
+}
+
+type lazyCar struct {
+	Id         int64
+	Owner      *lazyOwner
+	LazyOwner  Lazy[*lazyOwner]
+	Owners     []lazyOwner
+	LazyOwners Lazy[[]lazyOwner]
+}
+
+const findOwnerSql = "SELECT id, name FROM tbl_owner WHERE id = #{0}"
+
+func init() {
+	registerResultMap(&parser.ResultMap{
+		Id:      "test.carMap",
+		Results: []parser.ResultMapping{{Property: "id", Column: "id", Id: true}},
+		Associations: []parser.NestedMapping{
+			{Property: "owner", Select: findOwnerSql, Column: "owner_id"},
+			{Property: "lazyOwner", Select: findOwnerSql, Column: "owner_id", Lazy: true},
+		},
+		Collections: []parser.NestedMapping{
+			{Property: "owners", Select: "SELECT id, name FROM tbl_owner WHERE id = #{id}", Column: "{id=owner_id}"},
+			{Property: "lazyOwners", Select: findOwnerSql, Column: "owner_id", Lazy: true},
+		},
+	})
+}
+
+func countOwnerQueries(ts *testSession) int {
+	n := 0
+	for _, s := range ts.executed {
+		if strings.Contains(s, "tbl_owner") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestNestedSelect(t *testing.T) {
+	ts := newTestSession([]string{"id", "owner_id"},
+		[]interface{}{int64(1), int64(10)},
+		[]interface{}{int64(2), nil})
+	sess := newTestSqlSession(&ownerSession{ts}, "mysql")
+	ret, _ := ts.Query(context.Background(), "")
+	ts.executed = nil
+	ts.params = nil
+
+	var cars []lazyCar
+	if _, err := scanRows(context.Background(), sess, &cars, ret, "test.carMap"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countOwnerQueries(ts); n != 2 {
+		t.Fatal("expect 2 eager queries but get ", n)
+	}
+	car := cars[0]
+	if car.Owner == nil || car.Owner.Id != 10 || len(car.Owners) != 1 || car.Owners[0].Id != 10 || car.Owners[0].Name != "owner" {
+		t.Fatalf("unexpected car %+v", car)
+	}
+	// 多列参数{id=owner_id}绑定的必须是列值本身
+	for _, params := range ts.params {
+		if len(params) != 1 {
+			t.Fatalf("unexpected params %v", params)
+		}
+		if _, ok := params[0].(int64); !ok {
+			t.Fatalf("expect int64 param but get %T", params[0])
+		}
+	}
+	if car.LazyOwner.Loaded() || car.LazyOwners.Loaded() {
+		t.Fatal("lazy property must not be loaded")
+	}
+
+	owner, err := car.LazyOwner.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner == nil || owner.Id != 10 || !car.LazyOwner.Loaded() {
+		t.Fatalf("unexpected owner %+v", owner)
+	}
+	car.LazyOwner.Get()
+	if n := countOwnerQueries(ts); n != 3 {
+		t.Fatal("expect lazy property loaded once, queries: ", n)
+	}
+	owners, err := car.LazyOwners.Get()
+	if err != nil || len(owners) != 1 || owners[0].Id != 10 {
+		t.Fatalf("unexpected owners %+v %v", owners, err)
+	}
+
+	// owner_id为NULL时不执行嵌套select
+	if car := cars[1]; car.Owner != nil || car.Owners != nil || !car.LazyOwner.Loaded() {
+		t.Fatalf("unexpected car %+v", car)
+	}
+	if n := countOwnerQueries(ts); n != 4 {
+		t.Fatal("unexpected queries: ", n)
+	}
+}
+
+func TestParseSelectColumns(t *testing.T) {
+	cols := parseSelectColumns("{id = owner_id, type=owner_type}")
+	if len(cols) != 2 || cols[0] != (selectColumn{name: "id", column: "owner_id"}) || cols[1] != (selectColumn{name: "type", column: "owner_type"}) {
+		t.Fatalf("unexpected columns %+v", cols)
+	}
+	if cols := parseSelectColumns("owner_id"); len(cols) != 1 || cols[0].name != "" {
+		t.Fatalf("unexpected columns %+v", cols)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/parsing/sqlparser"
+)
+
+// SetLocalCache 设置之后创建的session是否开启一级缓存
+func (sm *SessionManager) SetLocalCache(enable bool) {
+	sm.localCache = enable
+}
+
+// SetLocalCache 开启或者关闭session的一级缓存，关闭时清空已缓存的结果
+// 一级缓存只在session内有效，以sql语句和参数为key，执行写语句、提交以及回滚时清空
+func (s *Session) SetLocalCache(enable bool) {
+	if enable {
+		if s.localCache == nil {
+			s.localCache = map[string]*cachedRows{}
+		}
+	} else {
+		s.localCache = nil
+	}
+}
+
+// ClearLocalCache 清空session的一级缓存
+func (s *Session) ClearLocalCache() {
+	if s != nil && s.localCache != nil {
+		s.localCache = map[string]*cachedRows{}
+	}
+}
+
+// clearLocalCacheAfter 执行select之外的语句后清空一级缓存
+func (s *Session) clearLocalCacheAfter(inv *Invocation) {
+	if inv.Action != sqlparser.SELECT || inv.Metadata == nil || inv.Metadata.Action != sqlparser.SELECT {
+		s.ClearLocalCache()
+	}
+}
+
+// fetchRows 读取查询的全部结果，开启一级缓存时优先从缓存中获取
+func (r *SelectRunner) fetchRows(ctx context.Context, inv *Invocation) (*cachedRows, error) {
+	var key string
+	local := r.sess != nil && r.sess.localCache != nil
+	if local {
+		key = paramsKey(inv.Metadata.PrepareSql, inv.Metadata.Params)
+		if rows, ok := r.sess.localCache[key]; ok {
+			return rows, nil
+		}
+	}
+	ret, err := r.session.Query(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
+	if err != nil {
+		return nil, err
+	}
+	defer ret.Close()
+	// 读取失败（包括迭代提前结束）时不缓存不完整的结果
+	rows, err := readRows(ret)
+	if err != nil {
+		return nil, err
+	}
+	if local {
+		r.sess.localCache[key] = rows
+	}
+	return rows, nil
+}
+
+// queryLocal 使用一级缓存执行查询
+func (r *SelectRunner) queryLocal(ctx context.Context, inv *Invocation) error {
+	rows, err := r.fetchRows(ctx, inv)
+	if err != nil {
+		r.logger.Warnln(err)
+		return err
+	}
+	inv.RowsAffected, err = scanRows(ctx, r.sess, inv.Bean, rows.result(), inv.Metadata.Attributes.ResultMap)
+	if err != nil {
+		r.logger.Warnln(err)
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLocalCache(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	sess := newTestSqlSession(ts, "mysql")
+	query := func(s *Session, id int64) {
+		var rows []testRow
+		if err := s.Select("SELECT id, name FROM tbl_user WHERE id = #{0}").Param(id).Result(&rows); err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 || rows[0].Name != "a" {
+			t.Fatalf("unexpected rows %+v", rows)
+		}
+	}
+
+	query(sess, 1)
+	query(sess, 1)
+	if n := countQueries(ts); n != 2 {
+		t.Fatal("local cache is disabled by default, queries: ", n)
+	}
+
+	sess.SetLocalCache(true)
+	query(sess, 1)
+	query(sess, 1)
+	if n := countQueries(ts); n != 3 {
+		t.Fatal("expect local cache hit, queries: ", n)
+	}
+	query(sess, 2)
+	if n := countQueries(ts); n != 4 {
+		t.Fatal("different params must not hit local cache, queries: ", n)
+	}
+
+	var count int64
+	if err := sess.Update("UPDATE tbl_user SET name = #{0}").Param("b").Result(&count); err != nil {
+		t.Fatal(err)
+	}
+	query(sess, 1)
+	if n := countQueries(ts); n != 5 {
+		t.Fatal("expect local cache cleared by update, queries: ", n)
+	}
+
+	_ = sess.Tx(context.Background(), func(s *Session) error {
+		query(s, 1)
+		return errors.New("rollback")
+	})
+	if n := countQueries(ts); n != 5 {
+		t.Fatal("expect local cache hit in tx, queries: ", n)
+	}
+	query(sess, 1)
+	if n := countQueries(ts); n != 6 {
+		t.Fatal("expect local cache cleared by rollback, queries: ", n)
+	}
+
+	sess.SetLocalCache(false)
+	query(sess, 1)
+	if n := countQueries(ts); n != 7 {
+		t.Fatal("expect local cache disabled, queries: ", n)
+	}
+}
+
+func TestLocalCacheFromManager(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	sm := newTestSessionManager(&testConnection{sessions: []*testSession{ts}}, "mysql")
+	sm.SetLocalCache(true)
+	sess, err := sm.createSession(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.localCache == nil {
+		t.Fatal("expect local cache enabled")
+	}
+}
+
+func TestLocalCacheRowsErr(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	ts.rowsErr = errors.New("broken connection")
+	sess := newTestSqlSession(ts, "mysql")
+	sess.SetLocalCache(true)
+
+	var rows []testRow
+	if err := sess.Select("SELECT id, name FROM tbl_user WHERE id = #{0}").Param(int64(1)).Result(&rows); !errors.Is(err, ts.rowsErr) {
+		t.Fatal("expect rows error but get ", err)
+	}
+	if len(sess.localCache) != 0 {
+		t.Fatal("partial result must not be cached")
+	}
+
+	ts.rowsErr = nil
+	if err := sess.Select("SELECT id, name FROM tbl_user WHERE id = #{0}").Param(int64(1)).Result(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if n := countQueries(ts); n != 2 || len(rows) != 1 {
+		t.Fatal("expect query after failure, queries: ", n)
+	}
+}
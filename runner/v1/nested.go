@@ -0,0 +1,372 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+)
+
+// nestedMap 包含association或者collection的resultMap，列序号已经按照列名前缀解析
+// 嵌套映射与MyBatis一致，不进行自动映射
+type nestedMap struct {
+	typ     reflect.Type
+	results []columnMapping
+	// keys 用于合并行的列，有id列时为id列，否则为全部映射的列
+	keys []int
+	// byId keys是否为id列
+	byId   bool
+	nested []*nestedField
+}
+
+type columnMapping struct {
+	result parser.ResultMapping
+	index  int
+}
+
+type nestedField struct {
+	property   string
+	collection bool
+	// ptr association字段或者collection元素是否为指针
+	ptr   bool
+	child *nestedMap
+	// selectId 嵌套select语句，不为空时child为nil，使用args对应的列值执行该语句获得属性值
+	selectId string
+	args     []selectArg
+	lazy     bool
+}
+
+// selectArg 传给嵌套select的列，name为空时直接使用列值作为参数
+type selectArg struct {
+	name  string
+	index int
+}
+
+// resultNode 解析过程中的对象，全部行处理完成后再赋值到上层对象中
+type resultNode struct {
+	value  reflect.Value
+	nested []*nestedNodes
+	// params 嵌套select的参数，与nestedMap.nested一一对应
+	params []interface{}
+}
+
+type nestedNodes struct {
+	keys  map[string]*resultNode
+	nodes []*resultNode
+}
+
+func scanNested(ctx context.Context, sess *Session, rv reflect.Value, isSlice, isPtr bool, et reflect.Type, result resultset.QueryResult, rm *parser.ResultMap) (int64, error) {
+	columns, err := result.Columns()
+	if err != nil {
+		return 0, err
+	}
+	indexes := make(map[string]int, len(columns))
+	for i, c := range columns {
+		indexes[strings.ToLower(c)] = i
+	}
+	m, err := compileNested(sess, rm, et, "", indexes, map[string]bool{})
+	if err != nil {
+		return 0, err
+	}
+	if !m.byId && hasIdResult(rm) {
+		xlog.Warnf("result map %s id columns not found in result set\n", rm.Id)
+		return 0, errors.ResultNameNotFound
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	roots := map[string]*resultNode{}
+	var order []*resultNode
+	for result.Next() {
+		if err := result.Scan(dest...); err != nil {
+			return 0, err
+		}
+		// 与MyBatis一致，没有id列或者id列全部为NULL时每一行都是一个新的对象
+		var (
+			key   string
+			found bool
+			node  *resultNode
+		)
+		if m.byId {
+			key, found = rowKey(values, m.keys)
+			node = roots[key]
+		}
+		if node == nil {
+			if node, err = m.newNode(values); err != nil {
+				return 0, err
+			}
+			if found {
+				roots[key] = node
+			}
+			order = append(order, node)
+		}
+		if err := m.apply(node, values); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, node := range order {
+		if err := m.finish(ctx, sess, node); err != nil {
+			return 0, err
+		}
+		ev := node.value
+		if !isPtr {
+			ev = ev.Elem()
+		}
+		if !isSlice {
+			rv.Set(ev)
+			return 1, nil
+		}
+		rv.Set(reflect.Append(rv, ev))
+	}
+	return int64(len(order)), nil
+}
+
+// compileNested 解析映射对应的列序号以及嵌套属性的类型，path用于检查循环引用
+func compileNested(sess *Session, rm *parser.ResultMap, t reflect.Type, prefix string, indexes map[string]int, path map[string]bool) (*nestedMap, error) {
+	m := &nestedMap{typ: t}
+	var ids []int
+	for _, r := range rm.Results {
+		idx, ok := indexes[strings.ToLower(prefix+r.Column)]
+		if !ok {
+			continue
+		}
+		m.results = append(m.results, columnMapping{result: r, index: idx})
+		if r.Id {
+			ids = append(ids, idx)
+		}
+	}
+	m.keys = ids
+	m.byId = len(ids) > 0
+	if len(m.keys) == 0 {
+		for _, r := range m.results {
+			m.keys = append(m.keys, r.index)
+		}
+	}
+
+	if rm.Id != "" {
+		path[rm.Id] = true
+		defer delete(path, rm.Id)
+	}
+	for _, n := range rm.Associations {
+		if err := m.compileField(sess, n, false, prefix, indexes, path); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range rm.Collections {
+		if err := m.compileField(sess, n, true, prefix, indexes, path); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *nestedMap) compileField(sess *Session, n parser.NestedMapping, collection bool, prefix string, indexes map[string]int, path map[string]bool) error {
+	child := n.ResultMap
+	if child == nil && n.Select == "" {
+		var ok bool
+		if child, ok = sess.findResultMap(n.ResultMapId); !ok {
+			xlog.Warnf("result map %s not found\n", n.ResultMapId)
+			return errors.ResultMapNotFound
+		}
+	}
+	if n.Select != "" {
+		return m.compileSelect(n, collection, prefix, indexes)
+	}
+	if path[child.Id] {
+		xlog.Warnf("result map %s is circular referenced by property %s, ignored\n", child.Id, n.Property)
+		return nil
+	}
+
+	ft, ok := fieldTypeByPath(m.typ, n.Property)
+	if !ok {
+		xlog.Warnf("result map property %s not found in %s\n", n.Property, m.typ)
+		return errors.ResultNameNotFound
+	}
+	if collection {
+		if ft.Kind() != reflect.Slice {
+			xlog.Warnf("result map collection %s is not a slice: %s\n", n.Property, ft)
+			return errors.ResultMapInvalid
+		}
+		ft = ft.Elem()
+	}
+	f := &nestedField{
+		property:   n.Property,
+		collection: collection,
+		ptr:        ft.Kind() == reflect.Ptr,
+	}
+	if f.ptr {
+		ft = ft.Elem()
+	}
+	if ft.Kind() != reflect.Struct {
+		xlog.Warnf("result map property %s is not a struct: %s\n", n.Property, ft)
+		return errors.ResultMapInvalid
+	}
+	var err error
+	f.child, err = compileNested(sess, child, ft, prefix+n.ColumnPrefix, indexes, path)
+	if err != nil {
+		return err
+	}
+	m.nested = append(m.nested, f)
+	return nil
+}
+
+func (m *nestedMap) newNode(values []interface{}) (*resultNode, error) {
+	v := reflect.New(m.typ)
+	for _, r := range m.results {
+		if values[r.index] == nil {
+			continue
+		}
+		if err := setProperty(v.Elem(), r.result, values[r.index]); err != nil {
+			return nil, err
+		}
+	}
+	node := &resultNode{
+		value:  v,
+		nested: make([]*nestedNodes, len(m.nested)),
+		params: make([]interface{}, len(m.nested)),
+	}
+	for i, f := range m.nested {
+		if f.selectId != "" {
+			node.params[i] = f.selectParam(values)
+		}
+	}
+	return node, nil
+}
+
+// apply 将当前行的嵌套对象合并到node中，key列全部为NULL时表示没有嵌套对象
+func (m *nestedMap) apply(node *resultNode, values []interface{}) error {
+	for i, f := range m.nested {
+		if f.selectId != "" {
+			continue
+		}
+		key, ok := rowKey(values, f.child.keys)
+		if !ok {
+			continue
+		}
+		nn := node.nested[i]
+		if nn == nil {
+			nn = &nestedNodes{keys: map[string]*resultNode{}}
+			node.nested[i] = nn
+		}
+		child, ok := nn.keys[key]
+		if !ok {
+			if !f.collection && len(nn.nodes) > 0 {
+				child = nn.nodes[0]
+			} else {
+				var err error
+				if child, err = f.child.newNode(values); err != nil {
+					return err
+				}
+				nn.keys[key] = child
+				nn.nodes = append(nn.nodes, child)
+			}
+		}
+		if err := f.child.apply(child, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish 将嵌套对象赋值到node的属性中，执行非延迟加载的嵌套select
+func (m *nestedMap) finish(ctx context.Context, sess *Session, node *resultNode) error {
+	for i, f := range m.nested {
+		if f.selectId != "" {
+			if node.params[i] == nil {
+				continue
+			}
+			field, err := fieldByPath(node.value.Elem(), f.property)
+			if err != nil {
+				return err
+			}
+			if err := f.load(ctx, sess, field, node.params[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		nn := node.nested[i]
+		if nn == nil {
+			continue
+		}
+		field, err := fieldByPath(node.value.Elem(), f.property)
+		if err != nil {
+			return err
+		}
+		if !f.collection {
+			child := nn.nodes[0]
+			if err := f.child.finish(ctx, sess, child); err != nil {
+				return err
+			}
+			field.Set(f.elem(child))
+			continue
+		}
+		slice := reflect.MakeSlice(field.Type(), 0, len(nn.nodes))
+		for _, child := range nn.nodes {
+			if err := f.child.finish(ctx, sess, child); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, f.elem(child))
+		}
+		field.Set(slice)
+	}
+	return nil
+}
+
+func (f *nestedField) elem(node *resultNode) reflect.Value {
+	if f.ptr {
+		return node.value
+	}
+	return node.value.Elem()
+}
+
+func hasIdResult(rm *parser.ResultMap) bool {
+	for _, r := range rm.Results {
+		if r.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// rowKey 使用指定列的值作为key，全部为NULL时返回false
+func rowKey(values []interface{}, indexes []int) (string, bool) {
+	b := strings.Builder{}
+	found := false
+	for _, i := range indexes {
+		v := values[i]
+		if v != nil {
+			found = true
+		}
+		if bs, ok := v.([]byte); ok {
+			v = string(bs)
+		}
+		b.WriteString(fmt.Sprintf("%T:%v", v, v))
+		b.WriteByte(0)
+	}
+	return b.String(), found
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"testing"
+)
+
+type nestedItem struct {
+	Id    int64
+	Sku   string
+	Count int
+}
+
+type nestedBuyer struct {
+	Id   int64
+	Name string
+}
+
+type nestedOrder struct {
+	Id    int64
+	No    string
+	Buyer *nestedBuyer
+	Items []nestedItem
+}
+
+func init() {
+	registerResultMap(&parser.ResultMap{
+		Id: "test.itemMap",
+		Results: []parser.ResultMapping{
+			{Property: "id", Column: "id", Id: true},
+			{Property: "sku", Column: "sku"},
+			{Property: "count", Column: "count"},
+		},
+	})
+	registerResultMap(&parser.ResultMap{
+		Id: "test.orderMap",
+		Results: []parser.ResultMapping{
+			{Property: "id", Column: "order_id", Id: true},
+			{Property: "no", Column: "order_no"},
+		},
+		Associations: []parser.NestedMapping{{
+			Property:     "buyer",
+			ColumnPrefix: "buyer_",
+			ResultMap: &parser.ResultMap{
+				Id: "test.orderMap.buyer",
+				Results: []parser.ResultMapping{
+					{Property: "id", Column: "id", Id: true},
+					{Property: "name", Column: "name"},
+				},
+			},
+		}},
+		Collections: []parser.NestedMapping{{
+			Property:     "items",
+			ColumnPrefix: "item_",
+			ResultMapId:  "test.itemMap",
+		}},
+	})
+}
+
+func TestNestedResultMap(t *testing.T) {
+	ts := newTestSession([]string{"order_id", "order_no", "buyer_id", "buyer_name", "item_id", "item_sku", "item_count"},
+		[]interface{}{int64(1), "o1", int64(10), "tom", int64(100), "a", int64(1)},
+		[]interface{}{int64(1), "o1", int64(10), "tom", int64(101), "b", int64(2)},
+		[]interface{}{int64(2), "o2", nil, nil, int64(102), "c", int64(3)},
+		[]interface{}{int64(1), "o1", int64(10), "tom", int64(101), "b", int64(2)},
+		[]interface{}{int64(3), "o3", int64(11), "jerry", nil, nil, nil})
+
+	ret, err := ts.Query(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var orders []*nestedOrder
+	n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &orders, ret, "test.orderMap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || len(orders) != 3 {
+		t.Fatal("expect 3 orders but get ", n)
+	}
+	if o := orders[0]; o.No != "o1" || o.Buyer == nil || o.Buyer.Name != "tom" || len(o.Items) != 2 || o.Items[1].Sku != "b" || o.Items[1].Count != 2 {
+		t.Fatalf("unexpected order %+v", o)
+	}
+	if o := orders[1]; o.No != "o2" || o.Buyer != nil || len(o.Items) != 1 || o.Items[0].Id != 102 {
+		t.Fatalf("unexpected order %+v", o)
+	}
+	if o := orders[2]; o.Buyer == nil || o.Buyer.Id != 11 || o.Items != nil {
+		t.Fatalf("unexpected order %+v", o)
+	}
+
+	ret, _ = ts.Query(context.Background(), "")
+	var order nestedOrder
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &order, ret, "test.orderMap"); err != nil {
+		t.Fatal(err)
+	}
+	if order.Id != 1 || len(order.Items) != 2 {
+		t.Fatalf("unexpected order %+v", order)
+	}
+}
+
+func TestNestedResultMapCircular(t *testing.T) {
+	type node struct {
+		Id       int64
+		Children []node
+	}
+	registerResultMap(&parser.ResultMap{
+		Id:      "test.nodeMap",
+		Results: []parser.ResultMapping{{Property: "id", Column: "id", Id: true}},
+		Collections: []parser.NestedMapping{{
+			Property:     "children",
+			ColumnPrefix: "child_",
+			ResultMapId:  "test.nodeMap",
+		}},
+	})
+	ts := newTestSession([]string{"id", "child_id"}, []interface{}{int64(1), int64(2)})
+	ret, _ := ts.Query(context.Background(), "")
+	var nodes []node
+	if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &nodes, ret, "test.nodeMap"); err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Id != 1 || nodes[0].Children != nil {
+		t.Fatalf("unexpected nodes %+v", nodes)
+	}
+}
+
+func TestNestedResultMapRoot(t *testing.T) {
+	registerResultMap(&parser.ResultMap{
+		Id: "test.orderNoIdMap",
+		Results: []parser.ResultMapping{
+			{Property: "no", Column: "order_no"},
+		},
+		Collections: []parser.NestedMapping{{
+			Property:     "items",
+			ColumnPrefix: "item_",
+			ResultMapId:  "test.itemMap",
+		}},
+	})
+	columns := []string{"order_id", "order_no", "item_id", "item_sku"}
+	rows := [][]interface{}{
+		{int64(1), "o1", int64(100), "a"},
+		{int64(1), "o1", int64(101), "b"},
+		{nil, "o2", int64(102), "c"},
+	}
+
+	t.Run("no id", func(t *testing.T) {
+		ret, _ := newTestSession(columns, rows...).Query(context.Background(), "")
+		var orders []nestedOrder
+		n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &orders, ret, "test.orderNoIdMap")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 3 || orders[0].No != "o1" || orders[1].No != "o1" || len(orders[1].Items) != 1 || orders[1].Items[0].Sku != "b" {
+			t.Fatalf("expect each row as a root but get %+v", orders)
+		}
+	})
+
+	t.Run("null id", func(t *testing.T) {
+		ret, _ := newTestSession(columns, rows...).Query(context.Background(), "")
+		var orders []nestedOrder
+		n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &orders, ret, "test.orderMap")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 || len(orders[0].Items) != 2 || orders[1].No != "o2" || orders[1].Items[0].Sku != "c" {
+			t.Fatalf("expect null id row kept but get %+v", orders)
+		}
+	})
+
+	t.Run("id column missing", func(t *testing.T) {
+		ret, _ := newTestSession([]string{"order_no", "item_id"}, []interface{}{"o1", int64(100)}).Query(context.Background(), "")
+		var orders []nestedOrder
+		_, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &orders, ret, "test.orderMap")
+		if !errors.Is(err, errors.ResultNameNotFound) {
+			t.Fatal("expect ResultNameNotFound but get ", err)
+		}
+	})
+}
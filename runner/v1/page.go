@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/dialect"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/mapping"
+	"strings"
+)
+
+// Page 分页参数，作为select语句的参数之一传入时自动改写为分页查询，不参与语句的参数解析
+type Page struct {
+	// Num 页码，从1开始，小于1时返回PageInvalid
+	Num int
+	// Size 每页行数，必须大于0
+	Size int
+	// Count 是否执行count语句获得总行数
+	Count bool
+}
+
+// Offset 跳过的行数
+func (p Page) Offset() int64 {
+	if p.Num <= 1 {
+		return 0
+	}
+	return int64(p.Num-1) * int64(p.Size)
+}
+
+// PageResult 分页查询结果，作为Result的bean时同时获得总行数以及当前页数据
+type PageResult[T any] struct {
+	Num  int
+	Size int
+	// Total 总行数，未执行count语句时为-1
+	Total int64
+	// Pages 总页数，未执行count语句时为-1
+	Pages int64
+	Items []T
+}
+
+func (r *PageResult[T]) itemsBean() interface{} {
+	return &r.Items
+}
+
+func (r *PageResult[T]) setPage(page Page, total int64) {
+	r.Num = page.Num
+	r.Size = page.Size
+	r.Total = total
+	r.Pages = -1
+	if total >= 0 && page.Size > 0 {
+		r.Pages = (total + int64(page.Size) - 1) / int64(page.Size)
+	}
+	if r.Items == nil {
+		r.Items = []T{}
+	}
+}
+
+type pageBean interface {
+	itemsBean() interface{}
+	setPage(page Page, total int64)
+}
+
+// QueryPage 分页查询，返回T类型的分页结果
+func QueryPage[T any](ctx context.Context, sess *Session, sqlId string, page Page, params ...interface{}) (*PageResult[T], error) {
+	ret := &PageResult[T]{}
+	err := sess.Select(sqlId).Context(ctx).Param(append([]interface{}{page}, params...)...).Result(ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// extractPage 从参数中取出分页参数
+func extractPage(params []interface{}) ([]interface{}, *Page) {
+	var page *Page
+	ret := params[:0:0]
+	for _, p := range params {
+		switch v := p.(type) {
+		case Page:
+			page = &v
+		case *Page:
+			if v != nil {
+				page = v
+			}
+		default:
+			ret = append(ret, p)
+		}
+	}
+	if page == nil {
+		return params, nil
+	}
+	return ret, page
+}
+
+// queryPage 执行count语句以及分页语句
+func (r *SelectRunner) queryPage(bean interface{}) error {
+	page := *r.page
+	if page.Num < 1 || page.Size <= 0 {
+		return r.execError(errors.PageInvalid, r.metadata)
+	}
+	items := bean
+	pb, isPage := bean.(pageBean)
+	if isPage {
+		items = pb.itemsBean()
+	}
+
+	md := r.metadata
+	sql := strings.TrimRight(strings.TrimSpace(md.PrepareSql), ";")
+	total := int64(-1)
+	if page.Count {
+		countMd := *md
+		countMd.PrepareSql = "SELECT COUNT(*) FROM (" + trimOrderBy(sql) + ") gobatis_count_"
+		err := r.invokeWith(&countMd, &total, r.count)
+		if err != nil {
+			return err
+		}
+	}
+
+	if total != 0 {
+		if err := r.invokeWith(r.pagedMetadata(page), items, r.query); err != nil {
+			return err
+		}
+	}
+	if isPage {
+		pb.setPage(page, total)
+	}
+	return nil
+}
+
+// pagedMetadata 使用方言的分页语法改写语句，分页参数追加在原参数之后
+func (r *SelectRunner) pagedMetadata(page Page) *parser.Metadata {
+	md := r.metadata
+	sql := strings.TrimRight(strings.TrimSpace(md.PrepareSql), ";")
+	pagedMd := *md
+	var params []interface{}
+	pagedMd.PrepareSql, params = dialect.Select(r.driver).Paginate(sql, len(md.Params)+1, page.Offset(), int64(page.Size))
+	pagedMd.Params = append(append([]interface{}{}, md.Params...), params...)
+	return &pagedMd
+}
+
+func (r *SelectRunner) count(ctx context.Context, inv *Invocation) error {
+	ret, err := r.session.Query(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
+	if err != nil {
+		r.logger.Warnln(err)
+		return err
+	}
+	defer ret.Close()
+	if _, err = mapping.ScanRows(inv.Bean, ret); err != nil {
+		r.logger.Warnln(err)
+		return err
+	}
+	inv.RowsAffected = 1
+	return nil
+}
+
+// trimOrderBy 去掉语句末尾最外层的ORDER BY，count不需要排序，并且SQL Server不允许子查询中使用没有TOP或者OFFSET的ORDER BY
+// ORDER BY之后包含LIMIT、OFFSET或者FETCH时保持不变
+func trimOrderBy(sql string) string {
+	upper := strings.ToUpper(sql)
+	pos := -1
+	depth := 0
+	quoted := false
+	for i := 0; i < len(upper); i++ {
+		switch c := upper[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(upper[i:], "ORDER") && (i == 0 || !isIdentChar(upper[i-1])):
+			rest := upper[i+len("ORDER"):]
+			trimmed := strings.TrimLeft(rest, " \t\r\n")
+			if len(trimmed) < len(rest) && strings.HasPrefix(trimmed, "BY") {
+				pos = i
+			}
+		}
+	}
+	if pos < 0 {
+		return sql
+	}
+	for _, f := range strings.Fields(upper[pos:]) {
+		switch f {
+		case "LIMIT", "OFFSET", "FETCH":
+			return sql
+		}
+	}
+	return strings.TrimSpace(sql[:pos])
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
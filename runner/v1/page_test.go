@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/lean/resultset"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pageSession count语句返回固定的总行数
+type pageSession struct {
+	*testSession
+	total int64
+}
+
+func (s *pageSession) Query(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	if strings.HasPrefix(stmt, "SELECT COUNT(*)") {
+		s.executed = append(s.executed, stmt)
+		s.params = append(s.params, params)
+		return &testResult{
+			SliceResult: resultset.NewSliceResult([][]interface{}{{s.total}}, []string{"count"}, testRowSetter),
+		}, nil
+	}
+	return s.testSession.Query(ctx, stmt, params...)
+}
+
+func TestPage(t *testing.T) {
+	sql := "SELECT id, name FROM tbl_user WHERE name = #{0}"
+	for _, c := range []struct {
+		driver string
+		sql    string
+		params []interface{}
+	}{
+		{"mysql", "SELECT id, name FROM tbl_user WHERE name = ? LIMIT ? OFFSET ?", []interface{}{"a", int64(2), int64(2)}},
+		{"postgres", "SELECT id, name FROM tbl_user WHERE name = $1 LIMIT $2 OFFSET $3", []interface{}{"a", int64(2), int64(2)}},
+		{"adodb", "SELECT id, name FROM tbl_user WHERE name = ? ORDER BY (SELECT NULL) OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{"a", int64(2), int64(2)}},
+		{"oci8", "SELECT * FROM (SELECT gobatis_t_.*, ROWNUM gobatis_rn_ FROM (SELECT id, name FROM tbl_user WHERE name = :1) gobatis_t_ WHERE ROWNUM <= :2) WHERE gobatis_rn_ > :3", []interface{}{"a", int64(4), int64(2)}},
+	} {
+		ts := &pageSession{
+			testSession: newTestSession([]string{"id", "name"}, []interface{}{int64(3), "a"}, []interface{}{int64(4), "a"}),
+			total:       5,
+		}
+		sess := newTestSqlSession(ts, c.driver)
+		ret, err := QueryPage[testRow](context.Background(), sess, sql, Page{Num: 2, Size: 2, Count: true}, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ret.Total != 5 || ret.Pages != 3 || len(ret.Items) != 2 || ret.Items[1].Id != 4 {
+			t.Fatalf("%s unexpected result %+v", c.driver, ret)
+		}
+		if !strings.HasPrefix(ts.executed[0], "SELECT COUNT(*) FROM (") {
+			t.Fatal(c.driver, " unexpected count sql ", ts.executed[0])
+		}
+		if ts.executed[1] != c.sql {
+			t.Fatal(c.driver, " unexpected sql ", ts.executed[1])
+		}
+		if !reflect.DeepEqual(ts.params[1], c.params) {
+			t.Fatal(c.driver, " unexpected params ", ts.params[1])
+		}
+	}
+}
+
+func TestPageWithoutCount(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	sess := newTestSqlSession(ts, "mysql")
+	var rows []testRow
+	err := sess.Select("SELECT id, name FROM tbl_user").Param(&Page{Num: 1, Size: 10}).Result(&rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts.executed) != 1 || ts.executed[0] != "SELECT id, name FROM tbl_user LIMIT ? OFFSET ?" {
+		t.Fatal("unexpected sql ", ts.executed)
+	}
+	if len(rows) != 1 {
+		t.Fatal("expect 1 row but get ", len(rows))
+	}
+
+	empty := &pageSession{testSession: newTestSession([]string{"id", "name"})}
+	ret, err := QueryPage[testRow](context.Background(), newTestSqlSession(empty, "mysql"), "SELECT id, name FROM tbl_user", Page{Num: 1, Size: 10, Count: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Total != 0 || ret.Pages != 0 || ret.Items == nil || len(empty.executed) != 1 {
+		t.Fatalf("unexpected result %+v %v", ret, empty.executed)
+	}
+}
+
+func TestPageSqlServerOrderBy(t *testing.T) {
+	ts := &pageSession{
+		testSession: newTestSession([]string{"id", "name"}, []interface{}{int64(3), "a"}),
+		total:       3,
+	}
+	sess := newTestSqlSession(ts, "sqlserver")
+	_, err := QueryPage[testRow](context.Background(), sess, "SELECT id, name FROM tbl_user WHERE name = #{0} ORDER BY id DESC", Page{Num: 2, Size: 2, Count: true}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.executed[0] != "SELECT COUNT(*) FROM (SELECT id, name FROM tbl_user WHERE name = @p1) gobatis_count_" {
+		t.Fatal("unexpected count sql ", ts.executed[0])
+	}
+	if ts.executed[1] != "SELECT id, name FROM tbl_user WHERE name = @p1 ORDER BY id DESC OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY" {
+		t.Fatal("unexpected sql ", ts.executed[1])
+	}
+}
+
+func TestTrimOrderBy(t *testing.T) {
+	for sql, expect := range map[string]string{
+		"SELECT * FROM t ORDER BY id":                                      "SELECT * FROM t",
+		"SELECT * FROM t order\nby id, name desc":                          "SELECT * FROM t",
+		"SELECT * FROM (SELECT * FROM t ORDER BY id) a":                    "SELECT * FROM (SELECT * FROM t ORDER BY id) a",
+		"SELECT * FROM t WHERE name = 'ORDER BY' ":                         "SELECT * FROM t WHERE name = 'ORDER BY' ",
+		"SELECT * FROM t ORDER BY id LIMIT 10":                             "SELECT * FROM t ORDER BY id LIMIT 10",
+		"SELECT * FROM t ORDER BY id OFFSET 1 ROWS FETCH NEXT 1 ROWS ONLY": "SELECT * FROM t ORDER BY id OFFSET 1 ROWS FETCH NEXT 1 ROWS ONLY",
+		"SELECT border_id FROM t":                                          "SELECT border_id FROM t",
+	} {
+		if got := trimOrderBy(sql); got != expect {
+			t.Fatalf("%s: expect %s but get %s", sql, expect, got)
+		}
+	}
+}
+
+func TestPageInvalid(t *testing.T) {
+	sess := newTestSqlSession(newTestSession([]string{"id", "name"}), "mysql")
+	for _, page := range []Page{{Num: 0, Size: 10}, {Num: 1, Size: 0}, {Num: 1, Size: -1}} {
+		var rows []testRow
+		err := sess.Select("SELECT id, name FROM tbl_user").Param(page).Result(&rows)
+		if !errors.Is(err, errors.PageInvalid) {
+			t.Fatalf("%+v expect PageInvalid but get %v", page, err)
+		}
+	}
+}
+
+func TestPageCursor(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(3), "a"})
+	sess := newTestSqlSession(ts, "mysql")
+	var row testRow
+	n := 0
+	err := sess.SelectCursor("SELECT id, name FROM tbl_user WHERE name = #{0}").Param("a", Page{Num: 2, Size: 2, Count: true}).Each(&row, func() error {
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts.executed) != 1 || ts.executed[0] != "SELECT id, name FROM tbl_user WHERE name = ? LIMIT ? OFFSET ?" {
+		t.Fatal("unexpected sql ", ts.executed)
+	}
+	if !reflect.DeepEqual(ts.params[0], []interface{}{"a", int64(2), int64(2)}) || n != 1 {
+		t.Fatal("unexpected params ", ts.params[0], n)
+	}
+
+	_, err = sess.SelectCursor("SELECT id, name FROM tbl_user").Param(Page{Num: 0, Size: 2}).Cursor()
+	if !errors.Is(err, errors.PageInvalid) {
+		t.Fatal("expect PageInvalid but get ", err)
+	}
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"github.com/xfali/lean/mapping"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/reflection"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+)
+
+// scanRows 将查询结果解析到bean中，指定resultMap时按照resultMap映射
+// sess用于执行resultMap中的嵌套select
+func scanRows(ctx context.Context, sess *Session, bean interface{}, result resultset.QueryResult, resultMapId string) (int64, error) {
+	if resultMapId == "" {
+		// 字段需要使用处理器时按照自动映射的resultMap解析
+		if rm := typeHandlerResultMap(bean); rm != nil {
+			return scanResultMap(ctx, sess, bean, result, rm)
+		}
+		return mapping.ScanRows(bean, result)
+	}
+	rm, ok := sess.findResultMap(resultMapId)
+	if !ok {
+		xlog.Warnf("result map %s not found\n", resultMapId)
+		return 0, errors.ResultMapNotFound
+	}
+	return scanResultMap(ctx, sess, bean, result, rm)
+}
+
+// findResultMap 在session使用的解析器注册表中查找resultMap
+func (s *Session) findResultMap(id string) (*parser.ResultMap, bool) {
+	if r, ok := s.registry.(parser.ResultMapRegistry); ok {
+		return r.FindResultMap(id)
+	}
+	return nil, false
+}
+
+// scanResultMap 按照resultMap解析结果，bean为结构体、结构体slice或者结构体指针slice的指针
+// 其他类型不适用resultMap，按照列名映射
+func scanResultMap(ctx context.Context, sess *Session, bean interface{}, result resultset.QueryResult, rm *parser.ResultMap) (int64, error) {
+	rv := reflect.Indirect(reflect.ValueOf(bean))
+	isSlice := rv.Kind() == reflect.Slice
+	et := rv.Type()
+	if isSlice {
+		et = et.Elem()
+	}
+	if rm.Discriminator != nil && discriminable(et) {
+		return scanDiscriminated(sess, rv, isSlice, et, result, rm)
+	}
+	if rm.Constructor != nil {
+		return scanConstructed(sess, rv, isSlice, et, result, rm)
+	}
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct || mapping.TimeType.AssignableTo(et) {
+		return mapping.ScanRows(bean, result)
+	}
+
+	if rm.HasNested() {
+		return scanNested(ctx, sess, rv, isSlice, isPtr, et, result, rm)
+	}
+
+	columns, err := result.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if rm.AutoMapping {
+		rm = withTypeHandlerResults(rm, et)
+	}
+	m := newRowMapper(rm, columns)
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var count int64
+	for result.Next() {
+		if err := result.Scan(dest...); err != nil {
+			return count, err
+		}
+		ev := reflect.New(et)
+		if err := m.mapRow(ev.Elem(), values); err != nil {
+			return count, err
+		}
+		if !isPtr {
+			ev = ev.Elem()
+		}
+		count++
+		if !isSlice {
+			rv.Set(ev)
+			break
+		}
+		rv.Set(reflect.Append(rv, ev))
+	}
+	return count, nil
+}
+
+type rowMapper struct {
+	rm      *parser.ResultMap
+	columns []string
+	// indexes 每个映射对应的列序号，-1表示结果中没有该列
+	indexes []int
+	// autoColumns 没有配置映射、按照列名自动映射的列序号
+	autoColumns []int
+}
+
+func newRowMapper(rm *parser.ResultMap, columns []string) *rowMapper {
+	m := &rowMapper{
+		rm:      rm,
+		columns: columns,
+		indexes: make([]int, len(rm.Results)),
+	}
+	mapped := make([]bool, len(columns))
+	for i, r := range rm.Results {
+		m.indexes[i] = -1
+		for j, c := range columns {
+			if strings.EqualFold(c, r.Column) {
+				m.indexes[i] = j
+				mapped[j] = true
+				break
+			}
+		}
+	}
+	if rm.AutoMapping {
+		for i := range columns {
+			if !mapped[i] {
+				m.autoColumns = append(m.autoColumns, i)
+			}
+		}
+	}
+	return m
+}
+
+func (m *rowMapper) mapRow(dst reflect.Value, values []interface{}) error {
+	for i, r := range m.rm.Results {
+		idx := m.indexes[i]
+		if idx < 0 || values[idx] == nil {
+			continue
+		}
+		if err := setProperty(dst, r, values[idx]); err != nil {
+			return err
+		}
+	}
+	if len(m.autoColumns) == 0 {
+		return nil
+	}
+	columns := make([]string, len(m.autoColumns))
+	row := make([]interface{}, len(m.autoColumns))
+	for i, idx := range m.autoColumns {
+		columns[i] = m.columns[idx]
+		row[i] = values[idx]
+	}
+	_, err := mapping.ScanRows2Value(dst, (&cachedRows{columns: columns, rows: [][]interface{}{row}}).result())
+	return err
+}
+
+// setProperty 设置属性值，property使用.分隔嵌套属性，路径上的nil指针会被创建
+// 优先使用映射或者字段tag指定的处理器，其次使用按照字段类型注册的处理器
+func setProperty(dst reflect.Value, r parser.ResultMapping, value interface{}) error {
+	property := r.Property
+	v, err := fieldByPath(dst, property)
+	if err != nil {
+		return err
+	}
+	name := r.TypeHandler
+	if name == "" {
+		if f, ok := structFieldByPath(dst.Type(), property); ok {
+			name = f.Tag.Get(typehandler.TagName)
+		}
+	}
+	if ok, err := typehandler.Scan(value, v, name, r.JdbcType); ok || err != nil {
+		if err != nil {
+			xlog.Warnf("result map property %s type handler failed: %v\n", property, err)
+		}
+		return err
+	}
+	if v.Kind() == reflect.Ptr {
+		pv := reflect.New(v.Type().Elem())
+		if !reflection.SetValue(pv.Elem(), reflect.ValueOf(value)) {
+			xlog.Warnf("result map property %s cannot set value %v\n", property, value)
+			return errors.ResultSetValueFailed
+		}
+		v.Set(pv)
+		return nil
+	}
+	if !reflection.SetValue(v, reflect.ValueOf(value)) {
+		xlog.Warnf("result map property %s cannot set value %v\n", property, value)
+		return errors.ResultSetValueFailed
+	}
+	return nil
+}
+
+// fieldByPath 按照.分隔的属性路径查找字段，路径上的nil指针会被创建
+func fieldByPath(dst reflect.Value, property string) (reflect.Value, error) {
+	names := strings.Split(property, ".")
+	v := dst
+	for i, name := range names {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			xlog.Warnf("result map property %s: %s is not a struct\n", property, strings.Join(names[:i], "."))
+			return v, errors.ResultSetValueFailed
+		}
+		idx := findField(v.Type(), name)
+		if idx < 0 {
+			xlog.Warnf("result map property %s not found in %s\n", property, v.Type())
+			return v, errors.ResultNameNotFound
+		}
+		v = v.Field(idx)
+	}
+	return v, nil
+}
+
+// fieldTypeByPath 按照.分隔的属性路径查找字段类型
+func fieldTypeByPath(t reflect.Type, property string) (reflect.Type, bool) {
+	f, ok := structFieldByPath(t, property)
+	if !ok {
+		return nil, false
+	}
+	return f.Type, true
+}
+
+// structFieldByPath 按照.分隔的属性路径查找字段
+func structFieldByPath(t reflect.Type, property string) (reflect.StructField, bool) {
+	var f reflect.StructField
+	for _, name := range strings.Split(property, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return f, false
+		}
+		idx := findField(t, name)
+		if idx < 0 {
+			return f, false
+		}
+		f = t.Field(idx)
+		t = f.Type
+	}
+	return f, true
+}
+
+// findField 按照column tag、字段名以及忽略大小写的字段名查找字段，返回字段序号，没有找到时返回-1
+func findField(t reflect.Type, name string) int {
+	fold := -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Name == name || f.Tag.Get(mapping.FieldAliasTagName) == name {
+			return i
+		}
+		if fold < 0 && strings.EqualFold(f.Name, name) {
+			fold = i
+		}
+	}
+	return fold
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"testing"
+)
+
+type resultMapAddress struct {
+	City string
+}
+
+type resultMapUser struct {
+	UserId   int64
+	UserName string
+	Age      int
+	Address  *resultMapAddress
+}
+
+func init() {
+	registerResultMap(&parser.ResultMap{
+		Id:          "test.userMap",
+		AutoMapping: true,
+		Results: []parser.ResultMapping{
+			{Property: "userId", Column: "id", Id: true},
+			{Property: "userName", Column: "name"},
+			{Property: "address.city", Column: "city"},
+		},
+	})
+}
+
+func TestResultMap(t *testing.T) {
+	ts := newTestSession([]string{"ID", "name", "city", "Age"},
+		[]interface{}{int64(1), []byte("a"), "x", int64(10)},
+		[]interface{}{int64(2), "b", nil, int64(20)})
+	sess := newTestSqlSession(ts, "mysql")
+	sql := "SELECT id, name, city, age FROM tbl_user"
+	p, err := sess.ParserFactory(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp := &attrParser{Parser: p, attrs: parser.Attributes{ResultMap: "test.userMap"}}
+
+	var users []*resultMapUser
+	if err := sess.createSelect(sql, rp).Param().Result(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatal("expect 2 rows but get ", len(users))
+	}
+	if u := users[0]; u.UserId != 1 || u.UserName != "a" || u.Age != 10 || u.Address == nil || u.Address.City != "x" {
+		t.Fatalf("unexpected user %+v", u)
+	}
+	if u := users[1]; u.UserId != 2 || u.UserName != "b" || u.Age != 20 || u.Address != nil {
+		t.Fatalf("unexpected user %+v", u)
+	}
+
+	var user resultMapUser
+	if err := sess.createSelect(sql, rp).Param().Result(&user); err != nil {
+		t.Fatal(err)
+	}
+	if user.UserId != 1 || user.Address.City != "x" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+
+	missing := &attrParser{Parser: p, attrs: parser.Attributes{ResultMap: "test.notExist"}}
+	if err := sess.createSelect(sql, missing).Param().Result(&users); !errors.Is(err, errors.ResultMapNotFound) {
+		t.Fatal("expect ResultMapNotFound but get ", err)
+	}
+}
+
+func TestResultMapWithoutAutoMapping(t *testing.T) {
+	registerResultMap(&parser.ResultMap{
+		Id:      "test.nameOnly",
+		Results: []parser.ResultMapping{{Property: "UserName", Column: "name"}},
+	})
+	ts := newTestSession([]string{"id", "name", "Age"}, []interface{}{int64(1), "a", int64(10)})
+	ret, err := ts.Query(context.Background(), "SELECT id, name, age FROM tbl_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []resultMapUser
+	n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &users, ret, "test.nameOnly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || users[0].UserName != "a" || users[0].UserId != 0 || users[0].Age != 0 {
+		t.Fatalf("unexpected users %+v", users)
+	}
+}
+
+func TestResultMapRegistry(t *testing.T) {
+	ts := newTestSession([]string{"id", "name"}, []interface{}{int64(1), "a"})
+	sess := newTestSqlSession(ts, "mysql")
+	sql := "SELECT id, name FROM tbl_user"
+	p, err := sess.ParserFactory(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp := &attrParser{Parser: p, attrs: parser.Attributes{ResultMap: "test.registryMap"}}
+
+	registry := parser.NewRegistry()
+	registry.AddResultMap(&parser.ResultMap{
+		Id:      "test.registryMap",
+		Results: []parser.ResultMapping{{Property: "UserName", Column: "name"}},
+	})
+	var users []resultMapUser
+	if err := sess.createSelect(sql, rp).Param().Result(&users); !errors.Is(err, errors.ResultMapNotFound) {
+		t.Fatal("expect ResultMapNotFound in global registry but get ", err)
+	}
+	sess.registry = registry
+	if err := sess.createSelect(sql, rp).Param().Result(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].UserName != "a" || users[0].UserId != 0 {
+		t.Fatalf("unexpected users %+v", users)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+)
+
+// Savepoint 在当前事务中创建保存点
+func (s *Session) Savepoint(ctx context.Context, name string) error {
+	syntax := s.Dialect().Savepoint()
+	if syntax.Create == "" {
+		return errors.SavepointNotSupported
+	}
+	return s.execSavepoint(ctx, syntax.Create, name)
+}
+
+// RollbackToSavepoint 回滚到保存点，保存点之前的修改保留
+func (s *Session) RollbackToSavepoint(ctx context.Context, name string) error {
+	syntax := s.Dialect().Savepoint()
+	if syntax.Rollback == "" {
+		return errors.SavepointNotSupported
+	}
+	s.ClearLocalCache()
+	return s.execSavepoint(ctx, syntax.Rollback, name)
+}
+
+// ReleaseSavepoint 释放保存点，数据库不支持时忽略
+func (s *Session) ReleaseSavepoint(ctx context.Context, name string) error {
+	return s.execSavepoint(ctx, s.Dialect().Savepoint().Release, name)
+}
+
+func (s *Session) execSavepoint(ctx context.Context, format, name string) error {
+	if s.txDepth == 0 {
+		return errors.TransactionWithoutBegin
+	}
+	if !isSavepointName(name) {
+		return errors.SavepointNameInvalid
+	}
+	if format == "" {
+		return nil
+	}
+	ret, err := s.session.Execute(ctx, fmt.Sprintf(format, name))
+	if err != nil {
+		s.logger.Warnln(err)
+		return err
+	}
+	return ret.Close()
+}
+
+// nestedTx 在已开启的事务中使用保存点模拟嵌套事务
+func (s *Session) nestedTx(ctx context.Context, txFunc func(session *Session) error) (err error) {
+	s.savepointSeq++
+	name := fmt.Sprintf("gobatis_sp_%d", s.savepointSeq)
+	rollback := func(ctx context.Context) error {
+		return s.RollbackToSavepoint(ctx, name)
+	}
+	if e := s.traceTx(ctx, SpanBegin, name, func(ctx context.Context) error {
+		return s.Savepoint(ctx, name)
+	}); e != nil {
+		return e
+	}
+	s.txDepth++
+	defer func() {
+		s.txDepth--
+		if r := recover(); r != nil {
+			if e := s.traceTx(ctx, SpanRollback, name, rollback); e != nil {
+				s.logger.Warnf("Rollback to savepoint %s error: %v\n", name, e)
+			}
+			panic(r)
+		}
+	}()
+
+	if fnErr := txFunc(s); fnErr != nil {
+		e := s.traceTx(ctx, SpanRollback, name, rollback)
+		if e != nil {
+			s.logger.Warnf("Rollback to savepoint %s error: %v , business error: %v\n", name, e, fnErr)
+		}
+		return fnErr
+	}
+	return s.traceTx(ctx, SpanCommit, name, func(ctx context.Context) error {
+		return s.ReleaseSavepoint(ctx, name)
+	})
+}
+
+// 保存点名称会直接拼接到语句中，只允许字母、数字和下划线
+func isSavepointName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
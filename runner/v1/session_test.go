@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/cache"
+	"github.com/xfali/gobatis/v2/parsing/manager"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"github.com/xfali/lean/session"
+	"github.com/xfali/xlog"
+)
+
+type testRow struct {
+	Id   int64  `column:"id"`
+	Name string `column:"name"`
+}
+
+// testSession 记录执行过的语句，查询返回预置的数据
+type testSession struct {
+	session.Session
+	columns  []string
+	rows     [][]interface{}
+	executed []string
+	params   [][]interface{}
+	closed   int
+	// rowsErr 结果集迭代结束后Err返回的错误
+	rowsErr error
+}
+
+func newTestSession(columns []string, rows ...[]interface{}) *testSession {
+	return &testSession{
+		Session: session.NewDummySession(0),
+		columns: columns,
+		rows:    rows,
+	}
+}
+
+func (s *testSession) Query(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	s.executed = append(s.executed, stmt)
+	s.params = append(s.params, params)
+	return &testResult{
+		SliceResult: resultset.NewSliceResult(s.rows, s.columns, testRowSetter),
+		err:         s.rowsErr,
+		onClose: func() {
+			s.closed++
+		},
+	}, nil
+}
+
+func (s *testSession) Execute(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	s.executed = append(s.executed, stmt)
+	s.params = append(s.params, params)
+	return &testResult{
+		SliceResult: resultset.NewSliceResult([][]interface{}{{}}, nil, testRowSetter),
+	}, nil
+}
+
+func testRowSetter(d interface{}, columns []string, dest []interface{}) error {
+	row := d.([]interface{})
+	for i := range dest {
+		*(dest[i].(*interface{})) = row[i]
+	}
+	return nil
+}
+
+func (s *testSession) Begin(ctx context.Context) error {
+	s.executed = append(s.executed, "BEGIN")
+	return nil
+}
+
+func (s *testSession) Commit(ctx context.Context) error {
+	s.executed = append(s.executed, "COMMIT")
+	return nil
+}
+
+func (s *testSession) Rollback(ctx context.Context) error {
+	s.executed = append(s.executed, "ROLLBACK")
+	return nil
+}
+
+type testResult struct {
+	*resultset.SliceResult[[]interface{}]
+	onClose func()
+	err     error
+}
+
+func (r *testResult) Close() error {
+	if r.onClose != nil {
+		r.onClose()
+	}
+	return nil
+}
+
+func (r *testResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (r *testResult) Err() error {
+	return r.err
+}
+
+type testConnection struct {
+	sessions []*testSession
+}
+
+func (c *testConnection) Open() error {
+	return nil
+}
+
+func (c *testConnection) GetSession() (session.Session, error) {
+	sess := newTestSession(nil)
+	c.sessions = append(c.sessions, sess)
+	return sess, nil
+}
+
+func (c *testConnection) Close() error {
+	return nil
+}
+
+func newTestSessionManager(conn *testConnection, driver string) *SessionManager {
+	m, _ := manager.GetGlobalManagerRegistry().FindManager("xml")
+	return &SessionManager{
+		logger:        xlog.GetLogger(),
+		driverName:    driver,
+		conn:          conn,
+		registry:      manager.GetGlobalParserRegistry(),
+		ParserFactory: m.CreateDynamicStatementParser,
+		tracer:        NoopTracer,
+		stats:         newStatsCollector(DefaultLatencyBuckets),
+		caches:        newCacheRegistry(cache.DefaultFactory),
+	}
+}
+
+// registerResultMap 在全局解析器注册表中注册resultMap
+func registerResultMap(rm *parser.ResultMap) {
+	manager.GetGlobalParserRegistry().(parser.ResultMapRegistry).AddResultMap(rm)
+}
+
+func newTestSqlSession(sess session.Session, driver string) *Session {
+	m, _ := manager.GetGlobalManagerRegistry().FindManager("xml")
+	return &Session{
+		ctx:           context.Background(),
+		logger:        xlog.GetLogger(),
+		session:       sess,
+		driver:        driver,
+		registry:      manager.GetGlobalParserRegistry(),
+		ParserFactory: m.CreateDynamicStatementParser,
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/xfali/xlog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SlowQuery 慢查询信息
+type SlowQuery struct {
+	SqlId string
+	// Sql 实际执行的语句
+	Sql string
+	// Params 经过ParamRedactor处理后的参数
+	Params []interface{}
+	// Duration 执行耗时
+	Duration time.Duration
+	// Caller 调用gobatis的代码位置，格式为file:line
+	Caller string
+	// Err 执行错误
+	Err error
+}
+
+func (q *SlowQuery) String() string {
+	return fmt.Sprintf("sqlId: %s, cost: %v, caller: %s, sql: %s, params: %v, err: %v",
+		q.SqlId, q.Duration, q.Caller, q.Sql, q.Params, q.Err)
+}
+
+// SlowQuerySink 慢查询日志输出
+type SlowQuerySink interface {
+	Log(q *SlowQuery)
+}
+
+type SlowQuerySinkFunc func(q *SlowQuery)
+
+func (f SlowQuerySinkFunc) Log(q *SlowQuery) {
+	f(q)
+}
+
+// LoggerSink 使用xlog输出慢查询日志
+func LoggerSink(logger xlog.Logger) SlowQuerySink {
+	return SlowQuerySinkFunc(func(q *SlowQuery) {
+		logger.Warnf("Slow query: %s\n", q.String())
+	})
+}
+
+// ParamRedactor 在参数输出到慢查询日志之前处理参数，index为参数位置
+type ParamRedactor func(index int, value interface{}) interface{}
+
+// TruncateParams 将字符串以及[]byte类型的参数截断为最多maxLen个字符
+func TruncateParams(maxLen int) ParamRedactor {
+	return func(index int, value interface{}) interface{} {
+		switch v := value.(type) {
+		case string:
+			return truncateString(v, maxLen)
+		case []byte:
+			return truncateString(string(v), maxLen)
+		}
+		return value
+	}
+}
+
+// MaskParams 将所有参数替换为mask
+func MaskParams(mask string) ParamRedactor {
+	return func(index int, value interface{}) interface{} {
+		return mask
+	}
+}
+
+// DefaultParamRedactor 默认的参数处理策略
+var DefaultParamRedactor = TruncateParams(128)
+
+func truncateString(s string, maxLen int) string {
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen]) + "..."
+}
+
+type slowQueryConfig struct {
+	threshold time.Duration
+	sink      SlowQuerySink
+	redactor  ParamRedactor
+}
+
+// SetSlowQueryThreshold 设置慢查询阈值，执行时间超过阈值的语句输出到慢查询日志
+// 只对之后创建的session生效，0表示关闭
+func (sm *SessionManager) SetSlowQueryThreshold(threshold time.Duration) {
+	sm.slowQuery.threshold = threshold
+}
+
+// SetSlowQuerySink 设置慢查询日志输出，只对之后创建的session生效，nil表示使用session的logger
+func (sm *SessionManager) SetSlowQuerySink(sink SlowQuerySink) {
+	sm.slowQuery.sink = sink
+}
+
+// SetParamRedactor 设置慢查询日志的参数处理策略，只对之后创建的session生效，nil表示使用默认策略
+func (sm *SessionManager) SetParamRedactor(redactor ParamRedactor) {
+	sm.slowQuery.redactor = redactor
+}
+
+// slowQueryInvoker 记录执行时间超过阈值的语句
+func (s *Session) slowQueryInvoker(invoker Invoker) Invoker {
+	conf := s.slowQuery
+	if conf.threshold <= 0 {
+		return invoker
+	}
+	return func(ctx context.Context, inv *Invocation) error {
+		now := time.Now()
+		err := invoker(ctx, inv)
+		cost := time.Since(now)
+		if cost < conf.threshold {
+			return err
+		}
+
+		q := &SlowQuery{
+			SqlId:    inv.SqlId,
+			Duration: cost,
+			Caller:   callerLocation(),
+			Err:      err,
+		}
+		if inv.Metadata != nil {
+			redactor := conf.redactor
+			if redactor == nil {
+				redactor = DefaultParamRedactor
+			}
+			q.Sql = inv.Metadata.PrepareSql
+			q.Params = make([]interface{}, len(inv.Metadata.Params))
+			for i, v := range inv.Metadata.Params {
+				q.Params[i] = redactor(i, v)
+			}
+		}
+		sink := conf.sink
+		if sink == nil {
+			sink = LoggerSink(s.logger)
+		}
+		sink.Log(q)
+		return err
+	}
+}
+
+var runnerDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callerLocation 返回调用栈上第一个不属于runner的位置
+func callerLocation() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != runnerDir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowQuery(t *testing.T) {
+	conn := &testConnection{}
+	sm := newTestSessionManager(conn, "mysql")
+	var logs []*SlowQuery
+	sm.SetSlowQueryThreshold(time.Nanosecond)
+	sm.SetSlowQuerySink(SlowQuerySinkFunc(func(q *SlowQuery) {
+		logs = append(logs, q)
+	}))
+	sm.SetParamRedactor(TruncateParams(3))
+	sess := sm.NewSession()
+
+	var count int64
+	err := sess.Update("UPDATE tbl_user SET name = #{0} WHERE id = #{1}").Param("abcdef", 1).Result(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Fatal("expect 1 slow query but get ", len(logs))
+	}
+	q := logs[0]
+	if q.Sql != "UPDATE tbl_user SET name = ? WHERE id = ?" {
+		t.Fatal("unexpected sql ", q.Sql)
+	}
+	if q.Params[0] != "abc..." || q.Params[1] != 1 {
+		t.Fatal("unexpected params ", q.Params)
+	}
+	if !strings.Contains(q.Caller, "slowlog_test.go") {
+		t.Fatal("expect caller in slowlog_test.go but get ", q.Caller)
+	}
+
+	sm.SetSlowQueryThreshold(time.Hour)
+	sess = sm.NewSession()
+	if err := sess.Update("UPDATE tbl_user SET name = #{0}").Param("x").Result(&count); err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Fatal("expect no more slow query but get ", len(logs))
+	}
+}
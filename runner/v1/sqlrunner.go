@@ -19,16 +19,20 @@ package v1
 
 import (
 	"context"
+	"database/sql"
+	"github.com/xfali/gobatis/v2/cache"
 	"github.com/xfali/gobatis/v2/database/factory"
+	"github.com/xfali/gobatis/v2/dialect"
 	"github.com/xfali/gobatis/v2/errors"
 	"github.com/xfali/gobatis/v2/parsing/manager"
 	"github.com/xfali/gobatis/v2/parsing/parser"
 	"github.com/xfali/gobatis/v2/parsing/sqlparser"
 	"github.com/xfali/lean/connection"
-	"github.com/xfali/lean/mapping"
 	"github.com/xfali/lean/session"
 	"github.com/xfali/reflection"
 	"github.com/xfali/xlog"
+	"strings"
+	"time"
 )
 
 const (
@@ -43,6 +47,13 @@ type SessionManager struct {
 	conn          connection.Connection
 	registry      parser.Registry
 	ParserFactory ParserFactory
+	interceptors  []Interceptor
+	tracer        Tracer
+	stats         *statsCollector
+	slowQuery     slowQueryConfig
+	stmtTimeout   time.Duration
+	caches        *cacheRegistry
+	localCache    bool
 }
 
 func NewSessionManager(factory factory.Factory) *SessionManager {
@@ -53,6 +64,9 @@ func NewSessionManager(factory factory.Factory) *SessionManager {
 		conn:          factory.CreateConnection(),
 		registry:      manager.GetGlobalParserRegistry(),
 		ParserFactory: m.CreateDynamicStatementParser,
+		tracer:        NoopTracer,
+		stats:         newStatsCollector(DefaultLatencyBuckets),
+		caches:        newCacheRegistry(cache.DefaultFactory),
 	}
 }
 
@@ -70,6 +84,12 @@ type Runner interface {
 	Context(ctx context.Context) Runner
 }
 
+// RowsAffecter Session创建的Runner均实现了该接口
+type RowsAffecter interface {
+	// RowsAffected 写语句影响的行数，查询语句为读取的行数，Result之后有效
+	RowsAffected() int64
+}
+
 type Session struct {
 	ctx           context.Context
 	logger        xlog.Logger
@@ -77,17 +97,41 @@ type Session struct {
 	driver        string
 	registry      parser.Registry
 	ParserFactory ParserFactory
+	interceptors  []Interceptor
+	tracer        Tracer
+	stats         *statsCollector
+	slowQuery     slowQueryConfig
+	stmtTimeout   time.Duration
+	caches        *cacheRegistry
+	// pendingFlush 事务中修改过的namespace，提交后清空其二级缓存
+	pendingFlush map[string]struct{}
+	// localCache 一级缓存，nil表示未开启
+	localCache map[string]*cachedRows
+
+	txDepth      int
+	savepointSeq int
+	txReadOnly   bool
 }
 
 type BaseRunner struct {
+	sess     *Session
 	session  session.Session
 	parser   parser.Parser
+	sqlId    string
 	action   string
+	params   []interface{}
 	metadata *parser.Metadata
-	logger   xlog.Logger
-	driver   string
-	ctx      context.Context
-	runner   Runner
+	// renderTime 生成sql语句的耗时
+	renderTime   time.Duration
+	rowsAffected int64
+	// err 解析语句时的错误，在Result中返回
+	err error
+	// page 参数中的分页参数
+	page   *Page
+	logger xlog.Logger
+	driver string
+	ctx    context.Context
+	runner Runner
 }
 
 type SelectRunner struct {
@@ -113,48 +157,60 @@ type ExecRunner struct {
 
 // NewSession 使用一个session操作数据库
 func (sm *SessionManager) NewSession() *Session {
-	sess, err := sm.conn.GetSession()
+	sess, err := sm.createSession(context.Background())
 	if err != nil {
 		sm.logger.Errorln(err)
 		return nil
 	}
-	return &Session{
-		ctx:           context.Background(),
-		logger:        xlog.GetLogger(),
-		session:       sess,
-		driver:        sm.driverName,
-		registry:      sm.registry,
-		ParserFactory: sm.ParserFactory,
-	}
+	return sess
 }
 
 // Context 包含session的context
 func (sm *SessionManager) Context(ctx context.Context) context.Context {
-	sess, err := sm.conn.GetSession()
+	sqlSess, err := sm.createSession(ctx)
 	if err != nil {
 		sm.logger.Errorln(err)
 		return ctx
 	}
-	sqlSess := &Session{
+	return context.WithValue(ctx, ContextSessionKey, sqlSess)
+}
+
+func (sm *SessionManager) createSession(ctx context.Context) (*Session, error) {
+	sess, err := sm.conn.GetSession()
+	if err != nil {
+		return nil, err
+	}
+	ret := &Session{
 		ctx:           ctx,
 		logger:        xlog.GetLogger(),
 		session:       sess,
 		driver:        sm.driverName,
 		registry:      sm.registry,
 		ParserFactory: sm.ParserFactory,
+		interceptors:  append([]Interceptor(nil), sm.interceptors...),
+		tracer:        sm.tracer,
+		stats:         sm.stats,
+		slowQuery:     sm.slowQuery,
+		stmtTimeout:   sm.stmtTimeout,
+		caches:        sm.caches,
 	}
-	return context.WithValue(ctx, ContextSessionKey, sqlSess)
+	ret.SetLocalCache(sm.localCache)
+	return ret, nil
 }
 
 func WithSession(ctx context.Context, sess *Session) context.Context {
 	return context.WithValue(ctx, ContextSessionKey, sess)
 }
 
+// FindSession 获得context中的session，不存在时返回nil
 func FindSession(ctx context.Context) *Session {
 	if ctx == nil {
 		return nil
 	}
-	return ctx.Value(ContextSessionKey).(*Session)
+	if sess, ok := ctx.Value(ContextSessionKey).(*Session); ok {
+		return sess
+	}
+	return nil
 }
 
 func (sm *SessionManager) Close() error {
@@ -171,6 +227,11 @@ func (sm *SessionManager) SetParserRegistry(registry parser.Registry) {
 	sm.registry = registry
 }
 
+// Close 关闭session
+func (s *Session) Close() error {
+	return s.session.Close()
+}
+
 func (s *Session) SetContext(ctx context.Context) *Session {
 	s.ctx = ctx
 	return s
@@ -190,50 +251,108 @@ func (s *Session) SetParserRegistry(registry parser.Registry) {
 	s.registry = registry
 }
 
+// InTx 当前session是否处于事务中
+func (s *Session) InTx() bool {
+	return s.txDepth > 0
+}
+
+// Dialect 获得session驱动对应的数据库方言
+func (s *Session) Dialect() dialect.Dialect {
+	return dialect.Select(s.driver)
+}
+
 // Tx 开启事务执行语句
 // 返回nil则提交，返回error回滚
 // 抛出异常错误触发回滚
+// 在事务中再次调用Tx时使用保存点，内层返回error只回滚到保存点
 func (s *Session) Tx(ctx context.Context, txFunc func(session *Session) error) (err error) {
-	e1 := s.session.Begin(ctx)
+	return s.TxWithOptions(ctx, nil, txFunc)
+}
+
+// TxWithOptions 使用事务选项开启事务执行语句，opts为nil时同Tx
+// 隔离级别只在最外层事务生效，只读以及超时对内层事务同样生效
+func (s *Session) TxWithOptions(ctx context.Context, opts *TxOptions, txFunc func(session *Session) error) (err error) {
+	if opts != nil {
+		if opts.Timeout > 0 {
+			deadline := time.Now().Add(opts.Timeout)
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+			origin := s.ctx
+			sessCtx, sessCancel := context.WithDeadline(origin, deadline)
+			s.ctx = sessCtx
+			defer func() {
+				sessCancel()
+				s.ctx = origin
+			}()
+		}
+		if opts.ReadOnly && !s.txReadOnly {
+			s.txReadOnly = true
+			defer func() {
+				s.txReadOnly = false
+			}()
+		}
+	}
+
+	if s.txDepth > 0 {
+		if opts != nil && opts.Isolation != sql.LevelDefault {
+			s.logger.Warnf("Isolation level %s is ignored in nested transaction\n", opts.Isolation)
+		}
+		return s.nestedTx(ctx, txFunc)
+	}
+	if opts != nil && (opts.Isolation != sql.LevelDefault || opts.ReadOnly) {
+		ctx = factory.WithTxOptions(ctx, &sql.TxOptions{
+			Isolation: opts.Isolation,
+			ReadOnly:  opts.ReadOnly,
+		})
+	}
+
+	e1 := s.traceTx(ctx, SpanBegin, "", s.session.Begin)
 	if e1 != nil {
 		return e1
 	}
+	s.txDepth++
 	defer func(err *error) {
+		s.txDepth--
 		if r := recover(); r != nil {
-			*err = s.session.Rollback(ctx)
+			s.commitCacheFlush(true)
+			*err = s.traceTx(ctx, SpanRollback, "", s.session.Rollback)
 			panic(r)
 		}
 	}(&err)
 
 	if fnErr := txFunc(s); fnErr != nil {
-		e := s.session.Rollback(ctx)
+		s.commitCacheFlush(true)
+		e := s.traceTx(ctx, SpanRollback, "", s.session.Rollback)
 		if e != nil {
 			s.logger.Warnf("Rollback error: %v , business error: %v\n", e, fnErr)
 		}
 		return fnErr
 	} else {
-		return s.session.Commit(ctx)
+		// 提交失败时数据库状态未知，同样清空缓存
+		defer s.commitCacheFlush(false)
+		return s.traceTx(ctx, SpanCommit, "", s.session.Commit)
 	}
 }
 
 func (s *Session) Select(sql string) Runner {
-	return s.createSelect(s.findSqlParser(sql))
+	return s.createSelect(sql, s.findSqlParser(sql))
 }
 
 func (s *Session) Update(sql string) Runner {
-	return s.createUpdate(s.findSqlParser(sql))
+	return s.createUpdate(sql, s.findSqlParser(sql))
 }
 
 func (s *Session) Delete(sql string) Runner {
-	return s.createDelete(s.findSqlParser(sql))
+	return s.createDelete(sql, s.findSqlParser(sql))
 }
 
 func (s *Session) Insert(sql string) Runner {
-	return s.createInsert(s.findSqlParser(sql))
+	return s.createInsert(sql, s.findSqlParser(sql))
 }
 
 func (s *Session) Exec(sql string) Runner {
-	return s.createExec(s.findSqlParser(sql))
+	return s.createExec(sql, s.findSqlParser(sql))
 }
 
 func (baseRunner *BaseRunner) Param(params ...interface{}) Runner {
@@ -251,12 +370,23 @@ func (baseRunner *BaseRunner) Param(params ...interface{}) Runner {
 
 	if baseRunner.parser == nil {
 		baseRunner.logger.Warnf(errors.ParseParserNilError.Error())
-		return baseRunner
+		baseRunner.err = baseRunner.wrapError(errors.ParseParserNilError, errors.ParseParserNilError, "")
+		return baseRunner.runner
 	}
 
+	if _, ok := baseRunner.runner.(*SelectRunner); ok {
+		params, baseRunner.page = extractPage(params)
+	}
+	baseRunner.params = params
+	now := time.Now()
 	md, err := baseRunner.parser.ParseMetadata(baseRunner.driver, params...)
+	baseRunner.renderTime = time.Since(now)
 
 	if err == nil {
+		baseRunner.err = nil
+		if _, ok := baseRunner.runner.(*CallRunner); !ok && len(md.Outs) > 0 {
+			baseRunner.logger.Warnf("OUT parameters of %s are only written back by Session.Call\n", baseRunner.sqlId)
+		}
 		if baseRunner.action == "" || baseRunner.action == md.Action {
 			baseRunner.metadata = md
 		} else {
@@ -266,34 +396,78 @@ func (baseRunner *BaseRunner) Param(params ...interface{}) Runner {
 		}
 	} else {
 		baseRunner.logger.Warnf(err.Error())
+		baseRunner.metadata = nil
+		baseRunner.err = baseRunner.wrapError(err, errors.ParseDynamicSqlError, "")
 	}
 	return baseRunner.runner
 }
 
-//Context 设置执行的context
+// ready 检查语句是否已经解析，解析失败时返回解析的错误
+func (baseRunner *BaseRunner) ready() error {
+	if baseRunner.metadata != nil {
+		return nil
+	}
+	if baseRunner.err != nil {
+		return baseRunner.err
+	}
+	baseRunner.logger.Warnf("Sql Metadata is nil")
+	return baseRunner.wrapError(errors.RunnerNotReady, errors.RunnerNotReady, "")
+}
+
+// wrapError 为错误附加语句信息，err没有错误码时使用code的错误码
+func (baseRunner *BaseRunner) wrapError(err error, code error, sql string) error {
+	return errors.WithStatement(err, code, baseRunner.sqlId, sql, baseRunner.driver)
+}
+
+// execError 执行语句失败时的错误，查询语句默认使用StatementQueryError，其他语句使用StatementExecError
+func (baseRunner *BaseRunner) execError(err error, md *parser.Metadata) error {
+	code := errors.StatementExecError
+	if baseRunner.action == sqlparser.SELECT {
+		code = errors.StatementQueryError
+	}
+	sql := ""
+	if md != nil {
+		sql = md.PrepareSql
+	}
+	return baseRunner.wrapError(err, code, sql)
+}
+
+// Context 设置执行的context
 func (baseRunner *BaseRunner) Context(ctx context.Context) Runner {
 	baseRunner.ctx = ctx
 	return baseRunner.runner
 }
 
 func (r *SelectRunner) Result(bean interface{}) error {
-	if r.metadata == nil {
-		r.logger.Warnf("Sql Metadata is nil")
-		return errors.RunnerNotReady
+	if err := r.ready(); err != nil {
+		return err
 	}
 
 	if reflection.IsNil(bean) {
-		return errors.ResultPointerIsNil
+		return r.execError(errors.ResultPointerIsNil, r.metadata)
 	}
 
-	ret, err := r.session.Query(r.ctx, r.metadata.PrepareSql, r.metadata.Params...)
+	if r.page != nil {
+		return r.queryPage(bean)
+	}
+	return r.invoke(bean, r.query)
+}
+
+func (r *SelectRunner) query(ctx context.Context, inv *Invocation) error {
+	if c := r.sess.selectCache(inv.Metadata); c != nil {
+		return r.queryCache(ctx, inv, c)
+	}
+	if r.sess != nil && r.sess.localCache != nil {
+		return r.queryLocal(ctx, inv)
+	}
+	ret, err := r.session.Query(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
 	if err != nil {
 		r.logger.Warnln(err)
 		return err
 	}
 
 	defer ret.Close()
-	_, err = mapping.ScanRows(bean, ret)
+	inv.RowsAffected, err = scanRows(ctx, r.sess, inv.Bean, ret, inv.Metadata.Attributes.ResultMap)
 	if err != nil {
 		r.logger.Warnln(err)
 		return err
@@ -302,22 +476,47 @@ func (r *SelectRunner) Result(bean interface{}) error {
 }
 
 func (r *InsertRunner) Result(bean interface{}) error {
-	if r.metadata == nil {
-		r.logger.Warnf("Sql Metadata is nil")
-		return errors.RunnerNotReady
+	if err := r.ready(); err != nil {
+		return err
 	}
-	ret, err := r.session.Execute(r.ctx, r.metadata.PrepareSql, r.metadata.Params...)
+	return r.invoke(bean, r.insert)
+}
+
+func (r *InsertRunner) insert(ctx context.Context, inv *Invocation) error {
+	if err := r.sess.checkWritable(inv.Metadata); err != nil {
+		return err
+	}
+	attrs := inv.Metadata.Attributes
+	useKeys := attrs.UseGeneratedKeys && len(attrs.KeyProperty) > 0
+	d := dialect.Select(inv.Driver)
+	mode := d.KeyMode()
+	if useKeys && (mode == dialect.KeyModeReturning || mode == dialect.KeyModeOutput) {
+		if ok, err := r.insertReturning(ctx, inv, d); ok {
+			return err
+		}
+	}
+	ret, err := r.session.Execute(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
 	if err != nil {
 		r.logger.Warnln(err)
 		return err
 	}
 	defer ret.Close()
-	r.lastId, err = ret.LastInsertId()
-	if err != nil {
-		r.logger.Warnln(err)
+	// 不支持LastInsertId的数据库（如postgresql、oracle）不获取自增id，返回0
+	if mode == dialect.KeyModeFirstInsertId || mode == dialect.KeyModeLastInsertId {
+		r.lastId, err = ret.LastInsertId()
+		if err != nil {
+			r.logger.Warnln(err)
+		}
+	}
+	inv.LastInsertId = r.lastId
+	if i, e := ret.RowsAffected(); e == nil {
+		inv.RowsAffected = i
 	}
-	if reflection.CanSet(bean) {
-		err = reflection.SetValueInterface(bean, r.lastId)
+	if useKeys && err == nil && (mode == dialect.KeyModeFirstInsertId || mode == dialect.KeyModeLastInsertId) {
+		r.setInsertIds(inv, mode)
+	}
+	if reflection.CanSet(inv.Bean) {
+		err = reflection.SetValueInterface(inv.Bean, r.lastId)
 	}
 	return err
 }
@@ -327,139 +526,115 @@ func (r *InsertRunner) LastInsertId() int64 {
 }
 
 func (r *UpdateRunner) Result(bean interface{}) error {
-	if r.metadata == nil {
-		r.logger.Warnf("Sql Metadata is nil")
-		return errors.RunnerNotReady
-	}
-	ret, err := r.session.Execute(r.ctx, r.metadata.PrepareSql, r.metadata.Params...)
-	if err != nil {
-		r.logger.Warnln(err)
+	if err := r.ready(); err != nil {
 		return err
 	}
-	defer ret.Close()
-	i, err := ret.RowsAffected()
-	if err != nil {
-		r.logger.Warnln(err)
-	}
-
-	if reflection.CanSet(bean) {
-		err = reflection.SetValueInterface(bean, i)
-	}
-	return err
+	return r.invoke(bean, r.execute)
 }
 
 func (r *ExecRunner) Result(bean interface{}) error {
-	if r.metadata == nil {
-		r.logger.Warnf("Sql Metadata is nil")
-		return errors.RunnerNotReady
-	}
-	ret, err := r.session.Execute(r.ctx, r.metadata.PrepareSql, r.metadata.Params...)
-	if err != nil {
-		r.logger.Warnln(err)
+	if err := r.ready(); err != nil {
 		return err
 	}
-	defer ret.Close()
-	i, err := ret.RowsAffected()
-	if err != nil {
-		r.logger.Warnln(err)
-	}
-	if reflection.CanSet(bean) {
-		err = reflection.SetValueInterface(bean, i)
-	}
-	return err
+	return r.invoke(bean, r.execute)
 }
 
 func (r *DeleteRunner) Result(bean interface{}) error {
-	if r.metadata == nil {
-		r.logger.Warnf("Sql Metadata is nil")
-		return errors.RunnerNotReady
+	if err := r.ready(); err != nil {
+		return err
+	}
+	return r.invoke(bean, r.execute)
+}
+
+func (baseRunner *BaseRunner) Result(bean interface{}) error {
+	//FAKE RETURN
+	panic("Cannot be here")
+	//return nil, nil
+}
+
+// execute 执行update、delete以及exec语句，bean可设置时写入影响的行数
+func (baseRunner *BaseRunner) execute(ctx context.Context, inv *Invocation) error {
+	if err := baseRunner.sess.checkWritable(inv.Metadata); err != nil {
+		return err
 	}
-	ret, err := r.session.Execute(r.ctx, r.metadata.PrepareSql, r.metadata.Params...)
+	ret, err := baseRunner.session.Execute(ctx, inv.Metadata.PrepareSql, inv.Metadata.Params...)
 	if err != nil {
-		r.logger.Warnln(err)
+		baseRunner.logger.Warnln(err)
 		return err
 	}
 	defer ret.Close()
-	i, err := ret.RowsAffected()
+	inv.RowsAffected, err = ret.RowsAffected()
 	if err != nil {
-		r.logger.Warnln(err)
+		baseRunner.logger.Warnln(err)
 	}
-	if reflection.CanSet(bean) {
-		err = reflection.SetValueInterface(bean, i)
+	if reflection.CanSet(inv.Bean) {
+		err = reflection.SetValueInterface(inv.Bean, inv.RowsAffected)
 	}
 	return err
 }
 
-func (baseRunner *BaseRunner) Result(bean interface{}) error {
-	//FAKE RETURN
-	panic("Cannot be here")
-	//return nil, nil
+// checkWritable 只读事务中拒绝执行写语句
+func (s *Session) checkWritable(md *parser.Metadata) error {
+	if s == nil || !s.txReadOnly {
+		return nil
+	}
+	switch strings.ToLower(md.Action) {
+	case sqlparser.INSERT, sqlparser.UPDATE, sqlparser.DELETE:
+		return errors.TransactionReadOnly
+	}
+	return nil
 }
 
 func (baseRunner *BaseRunner) LastInsertId() int64 {
 	return -1
 }
 
-func (s *Session) createSelect(parser parser.Parser) Runner {
+func (baseRunner *BaseRunner) RowsAffected() int64 {
+	return baseRunner.rowsAffected
+}
+
+func (s *Session) createSelect(sqlId string, parser parser.Parser) Runner {
 	ret := &SelectRunner{}
-	ret.action = sqlparser.SELECT
-	ret.logger = s.logger
-	ret.session = s.session
-	ret.parser = parser
-	ret.ctx = s.ctx
-	ret.driver = s.driver
-	ret.runner = ret
+	s.initRunner(&ret.BaseRunner, sqlId, sqlparser.SELECT, parser, ret)
 	return ret
 }
 
-func (s *Session) createUpdate(parser parser.Parser) Runner {
+func (s *Session) createUpdate(sqlId string, parser parser.Parser) Runner {
 	ret := &UpdateRunner{}
-	ret.action = sqlparser.UPDATE
-	ret.logger = s.logger
-	ret.session = s.session
-	ret.parser = parser
-	ret.ctx = s.ctx
-	ret.driver = s.driver
-	ret.runner = ret
+	s.initRunner(&ret.BaseRunner, sqlId, sqlparser.UPDATE, parser, ret)
 	return ret
 }
 
-func (s *Session) createDelete(parser parser.Parser) Runner {
+func (s *Session) createDelete(sqlId string, parser parser.Parser) Runner {
 	ret := &DeleteRunner{}
-	ret.action = sqlparser.DELETE
-	ret.logger = s.logger
-	ret.session = s.session
-	ret.parser = parser
-	ret.ctx = s.ctx
-	ret.driver = s.driver
-	ret.runner = ret
+	s.initRunner(&ret.BaseRunner, sqlId, sqlparser.DELETE, parser, ret)
 	return ret
 }
 
-func (s *Session) createInsert(parser parser.Parser) Runner {
+func (s *Session) createInsert(sqlId string, parser parser.Parser) Runner {
 	ret := &InsertRunner{}
-	ret.action = sqlparser.INSERT
-	ret.logger = s.logger
-	ret.session = s.session
-	ret.parser = parser
-	ret.ctx = s.ctx
-	ret.driver = s.driver
-	ret.runner = ret
+	s.initRunner(&ret.BaseRunner, sqlId, sqlparser.INSERT, parser, ret)
 	return ret
 }
 
-func (s *Session) createExec(parser parser.Parser) Runner {
+func (s *Session) createExec(sqlId string, parser parser.Parser) Runner {
 	ret := &ExecRunner{}
-	ret.action = ""
-	ret.logger = s.logger
-	ret.session = s.session
-	ret.parser = parser
-	ret.ctx = s.ctx
-	ret.driver = s.driver
-	ret.runner = ret
+	s.initRunner(&ret.BaseRunner, sqlId, "", parser, ret)
 	return ret
 }
 
+func (s *Session) initRunner(base *BaseRunner, sqlId, action string, parser parser.Parser, runner Runner) {
+	base.sqlId = sqlId
+	base.action = action
+	base.logger = s.logger
+	base.sess = s
+	base.session = s.session
+	base.parser = parser
+	base.ctx = s.ctx
+	base.driver = s.driver
+	base.runner = runner
+}
+
 func (s *Session) findSqlParser(sqlId string) parser.Parser {
 	ret, ok := s.registry.FindParser(sqlId)
 	//FIXME: 当没有查找到sqlId对应的sql语句，则尝试使用sqlId直接操作数据库
@@ -0,0 +1,226 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets 执行耗时直方图默认的桶上限
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// RawSqlId 直接使用sql语句执行时统计使用的SqlId，避免统计数据以及Prometheus的sql_id标签随sql语句无限增长
+const RawSqlId = "<raw>"
+
+type StatementStats struct {
+	// SqlId 注册的语句id，直接使用sql语句执行时为RawSqlId
+	SqlId string
+	// Calls 执行次数
+	Calls int64
+	// Errors 执行失败次数
+	Errors int64
+	// Rows 查询读取的行数以及写语句影响的行数之和
+	Rows int64
+	// RenderTime 生成sql语句的总耗时
+	RenderTime time.Duration
+	// ExecTime 执行语句的总耗时
+	ExecTime time.Duration
+	// Buckets 执行耗时直方图，Buckets[i]为耗时不超过LatencyBuckets[i]且超过前一个桶上限的次数
+	// 最后一个元素为超过所有桶上限的次数
+	Buckets []int64
+}
+
+type Stats struct {
+	LatencyBuckets []time.Duration
+	// Statements 按照SqlId排序
+	Statements []StatementStats
+}
+
+type statsCollector struct {
+	buckets    []time.Duration
+	statements map[string]*StatementStats
+	lock       sync.Mutex
+}
+
+func newStatsCollector(buckets []time.Duration) *statsCollector {
+	return &statsCollector{
+		buckets:    buckets,
+		statements: map[string]*StatementStats{},
+	}
+}
+
+func (c *statsCollector) record(sqlId string, inv *Invocation, execTime time.Duration, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	st, ok := c.statements[sqlId]
+	if !ok {
+		st = &StatementStats{
+			SqlId:   sqlId,
+			Buckets: make([]int64, len(c.buckets)+1),
+		}
+		c.statements[sqlId] = st
+	}
+	st.Calls++
+	if err != nil {
+		st.Errors++
+	}
+	st.Rows += inv.RowsAffected
+	st.RenderTime += inv.RenderTime
+	st.ExecTime += execTime
+	st.Buckets[sort.Search(len(c.buckets), func(i int) bool {
+		return execTime <= c.buckets[i]
+	})]++
+}
+
+func (c *statsCollector) snapshot() Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := Stats{
+		LatencyBuckets: append([]time.Duration(nil), c.buckets...),
+		Statements:     make([]StatementStats, 0, len(c.statements)),
+	}
+	for _, st := range c.statements {
+		v := *st
+		v.Buckets = append([]int64(nil), st.Buckets...)
+		ret.Statements = append(ret.Statements, v)
+	}
+	sort.Slice(ret.Statements, func(i, j int) bool {
+		return ret.Statements[i].SqlId < ret.Statements[j].SqlId
+	})
+	return ret
+}
+
+func (c *statsCollector) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.statements = map[string]*StatementStats{}
+}
+
+// Stats 获得语句执行统计的快照
+func (sm *SessionManager) Stats() Stats {
+	return sm.stats.snapshot()
+}
+
+// ResetStats 清空语句执行统计
+func (sm *SessionManager) ResetStats() {
+	sm.stats.reset()
+}
+
+// SetLatencyBuckets 修改执行耗时直方图的桶上限，会清空已有的统计
+func (sm *SessionManager) SetLatencyBuckets(buckets []time.Duration) {
+	buckets = append([]time.Duration(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i] < buckets[j]
+	})
+	sm.stats.lock.Lock()
+	defer sm.stats.lock.Unlock()
+
+	sm.stats.buckets = buckets
+	sm.stats.statements = map[string]*StatementStats{}
+}
+
+// statsInvoker 统计语句执行，session不属于任何SessionManager时不统计
+func (s *Session) statsInvoker(invoker Invoker) Invoker {
+	if s.stats == nil {
+		return invoker
+	}
+	return func(ctx context.Context, inv *Invocation) error {
+		now := time.Now()
+		err := invoker(ctx, inv)
+		s.stats.record(s.statsSqlId(inv.SqlId), inv, time.Since(now), err)
+		return err
+	}
+}
+
+// statsSqlId 未注册的语句统计在RawSqlId下
+func (s *Session) statsSqlId(sqlId string) string {
+	if _, ok := s.registry.FindParser(sqlId); ok {
+		return sqlId
+	}
+	return RawSqlId
+}
+
+// WritePrometheus 将统计以Prometheus文本格式写入w
+func WritePrometheus(w io.Writer, stats Stats) error {
+	bw := bufio.NewWriter(w)
+	writeHeader := func(name, help, typ string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	writeHeader("gobatis_statement_calls_total", "Total number of statement executions.", "counter")
+	for _, st := range stats.Statements {
+		fmt.Fprintf(bw, "gobatis_statement_calls_total{sql_id=\"%s\"} %d\n", escapeLabel(st.SqlId), st.Calls)
+	}
+	writeHeader("gobatis_statement_errors_total", "Total number of failed statement executions.", "counter")
+	for _, st := range stats.Statements {
+		fmt.Fprintf(bw, "gobatis_statement_errors_total{sql_id=\"%s\"} %d\n", escapeLabel(st.SqlId), st.Errors)
+	}
+	writeHeader("gobatis_statement_rows_total", "Total number of rows returned or affected.", "counter")
+	for _, st := range stats.Statements {
+		fmt.Fprintf(bw, "gobatis_statement_rows_total{sql_id=\"%s\"} %d\n", escapeLabel(st.SqlId), st.Rows)
+	}
+	writeHeader("gobatis_statement_render_seconds_total", "Total time spent rendering statements.", "counter")
+	for _, st := range stats.Statements {
+		fmt.Fprintf(bw, "gobatis_statement_render_seconds_total{sql_id=\"%s\"} %s\n", escapeLabel(st.SqlId), formatSeconds(st.RenderTime))
+	}
+	writeHeader("gobatis_statement_exec_seconds", "Statement execution latency.", "histogram")
+	for _, st := range stats.Statements {
+		id := escapeLabel(st.SqlId)
+		var count int64
+		for i, b := range stats.LatencyBuckets {
+			if i < len(st.Buckets) {
+				count += st.Buckets[i]
+			}
+			fmt.Fprintf(bw, "gobatis_statement_exec_seconds_bucket{sql_id=\"%s\",le=\"%s\"} %d\n", id, formatSeconds(b), count)
+		}
+		fmt.Fprintf(bw, "gobatis_statement_exec_seconds_bucket{sql_id=\"%s\",le=\"+Inf\"} %d\n", id, st.Calls)
+		fmt.Fprintf(bw, "gobatis_statement_exec_seconds_sum{sql_id=\"%s\"} %s\n", id, formatSeconds(st.ExecTime))
+		fmt.Fprintf(bw, "gobatis_statement_exec_seconds_count{sql_id=\"%s\"} %d\n", id, st.Calls)
+	}
+	return bw.Flush()
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
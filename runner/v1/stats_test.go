@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	conn := &testConnection{}
+	sm := newTestSessionManager(conn, "mysql")
+	failed := errors.New("failed")
+	sm.AddInterceptor(InterceptorFunc(func(ctx context.Context, inv *Invocation, next Invoker) error {
+		if inv.Params[0] == "fail" {
+			return failed
+		}
+		return next(ctx, inv)
+	}))
+	sess := sm.NewSession()
+
+	sql := "test.statsUpdate"
+	p, err := sess.ParserFactory("UPDATE tbl_user SET name = #{0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = sess.registry.AddParser(sql, p)
+	var count int64
+	for i := 0; i < 2; i++ {
+		if err := sess.Update(sql).Param("x").Result(&count); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sess.Update(sql).Param("fail").Result(&count); !errors.Is(err, failed) {
+		t.Fatal("expect failed but get ", err)
+	}
+	// 直接执行的sql语句统计在同一个SqlId下
+	for _, raw := range []string{"DELETE FROM tbl_user WHERE name = #{0}", "DELETE FROM tbl_order WHERE name = #{0}"} {
+		if err := sess.Delete(raw).Param("x").Result(&count); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := sm.Stats()
+	if len(stats.Statements) != 2 {
+		t.Fatal("expect 2 statements but get ", len(stats.Statements))
+	}
+	if st := stats.Statements[0]; st.SqlId != RawSqlId || st.Calls != 2 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+	st := stats.Statements[1]
+	if st.SqlId != sql || st.Calls != 3 || st.Errors != 1 || st.Rows != 2 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+	var n int64
+	for _, v := range st.Buckets {
+		n += v
+	}
+	if n != 3 {
+		t.Fatal("expect 3 in histogram but get ", n)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := WritePrometheus(buf, stats); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"# TYPE gobatis_statement_exec_seconds histogram",
+		`gobatis_statement_calls_total{sql_id="test.statsUpdate"} 3`,
+		`gobatis_statement_errors_total{sql_id="test.statsUpdate"} 1`,
+		`gobatis_statement_exec_seconds_bucket{sql_id="test.statsUpdate",le="+Inf"} 3`,
+		`gobatis_statement_calls_total{sql_id="<raw>"} 2`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Fatal("expect ", s, " in ", out)
+		}
+	}
+
+	sm.ResetStats()
+	if len(sm.Stats().Statements) != 0 {
+		t.Fatal("expect empty stats after reset")
+	}
+}
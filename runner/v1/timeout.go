@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"time"
+)
+
+// SetStatementTimeout 设置默认的语句执行超时时间，只对之后创建的session生效
+// 语句定义了timeout属性时优先使用语句的设置，0表示不限制
+func (sm *SessionManager) SetStatementTimeout(timeout time.Duration) {
+	sm.stmtTimeout = timeout
+}
+
+// SetStatementTimeout 设置当前session默认的语句执行超时时间，0表示不限制
+func (s *Session) SetStatementTimeout(timeout time.Duration) {
+	s.stmtTimeout = timeout
+}
+
+// statementContext 根据语句的timeout属性或者默认超时时间为ctx设置deadline
+func (s *Session) statementContext(ctx context.Context, md *parser.Metadata) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var timeout time.Duration
+	if md != nil {
+		timeout = md.Attributes.Timeout
+	}
+	if timeout <= 0 && s != nil {
+		timeout = s.stmtTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutError 执行因超时失败时返回错误码为ExecutorTimeout的错误，原始错误可以通过errors.Is获得
+// err已经包含其他错误码时（如拦截器返回的Error）也使用ExecutorTimeout
+func timeoutError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return errors.WithCode(err, errors.ExecutorTimeout)
+	}
+	return err
+}
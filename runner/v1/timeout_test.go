@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/lean/resultset"
+	"testing"
+	"time"
+)
+
+// blockingSession 执行语句时阻塞直到ctx结束
+type blockingSession struct {
+	*testSession
+}
+
+func (s *blockingSession) Execute(ctx context.Context, stmt string, params ...interface{}) (resultset.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type attrParser struct {
+	parser.Parser
+	attrs parser.Attributes
+}
+
+func (p *attrParser) ParseMetadata(driverName string, params ...interface{}) (*parser.Metadata, error) {
+	md, err := p.Parser.ParseMetadata(driverName, params...)
+	if err == nil {
+		md.Attributes = p.attrs
+	}
+	return md, err
+}
+
+func TestStatementTimeout(t *testing.T) {
+	t.Run("session default", func(t *testing.T) {
+		sess := newTestSqlSession(&blockingSession{newTestSession(nil)}, "mysql")
+		sess.SetStatementTimeout(10 * time.Millisecond)
+		var count int64
+		err := sess.Update("UPDATE tbl_user SET name = #{0}").Param("x").Result(&count)
+		if !errors.Is(err, errors.ExecutorTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("expect timeout but get ", err)
+		}
+	})
+
+	t.Run("statement attribute", func(t *testing.T) {
+		sess := newTestSqlSession(&blockingSession{newTestSession(nil)}, "mysql")
+		sess.SetStatementTimeout(time.Hour)
+		sql := "UPDATE tbl_user SET name = #{0}"
+		p, err := sess.ParserFactory(sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var count int64
+		r := sess.createUpdate(sql, &attrParser{Parser: p, attrs: parser.Attributes{Timeout: 10 * time.Millisecond}})
+		err = r.Param("x").Result(&count)
+		if !errors.Is(err, errors.ExecutorTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("expect timeout but get ", err)
+		}
+	})
+
+	t.Run("interceptor error", func(t *testing.T) {
+		sess := newTestSqlSession(&blockingSession{newTestSession(nil)}, "mysql")
+		sess.SetStatementTimeout(10 * time.Millisecond)
+		// 拦截器将超时转换为包含其他错误码的Error时仍然返回ExecutorTimeout
+		sess.AddInterceptor(InterceptorFunc(func(ctx context.Context, inv *Invocation, next Invoker) error {
+			return errors.WithStatement(next(ctx, inv), errors.StatementExecError, "", "", "")
+		}))
+		var count int64
+		err := sess.Update("UPDATE tbl_user SET name = #{0}").Param("x").Result(&count)
+		if !errors.Is(err, errors.ExecutorTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("expect timeout but get ", err)
+		}
+	})
+
+	t.Run("no timeout", func(t *testing.T) {
+		sess := newTestSqlSession(newTestSession(nil), "mysql")
+		var count int64
+		if err := sess.Update("UPDATE tbl_user SET name = #{0}").Param("x").Result(&count); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+)
+
+const (
+	SpanStatement = "gobatis.statement"
+	SpanBegin     = "gobatis.tx.begin"
+	SpanCommit    = "gobatis.tx.commit"
+	SpanRollback  = "gobatis.tx.rollback"
+
+	TagSqlId        = "gobatis.sql_id"
+	TagAction       = "gobatis.action"
+	TagDriver       = "db.system"
+	TagStatement    = "db.statement"
+	TagRowsAffected = "gobatis.rows_affected"
+	TagSavepoint    = "gobatis.savepoint"
+)
+
+// Tracer 为语句执行以及事务操作创建span
+type Tracer interface {
+	// Start 开始一个span，返回的context包含该span，用于执行被追踪的操作
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	// SetTag 设置span的属性
+	SetTag(key string, value interface{})
+	// Finish 结束span，err不为nil时标记为失败
+	Finish(err error)
+}
+
+type noopTracer struct{}
+
+type noopSpan struct{}
+
+// NoopTracer 默认的Tracer，不做任何记录
+var NoopTracer Tracer = noopTracer{}
+
+func (t noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (s noopSpan) SetTag(key string, value interface{}) {}
+
+func (s noopSpan) Finish(err error) {}
+
+// SetTracer 设置Tracer，只对之后创建的session生效，nil表示不追踪
+func (sm *SessionManager) SetTracer(tracer Tracer) {
+	if tracer == nil {
+		tracer = NoopTracer
+	}
+	sm.tracer = tracer
+}
+
+// SetTracer 设置当前session使用的Tracer，nil表示不追踪
+func (s *Session) SetTracer(tracer Tracer) {
+	if tracer == nil {
+		tracer = NoopTracer
+	}
+	s.tracer = tracer
+}
+
+func (s *Session) getTracer() Tracer {
+	if s.tracer == nil {
+		return NoopTracer
+	}
+	return s.tracer
+}
+
+// traceInvoker 追踪语句执行，位于拦截器链的最外层，记录的是拦截器改写后的语句
+func (s *Session) traceInvoker(invoker Invoker) Invoker {
+	tracer := s.getTracer()
+	if tracer == NoopTracer {
+		return invoker
+	}
+	return func(ctx context.Context, inv *Invocation) error {
+		ctx, span := tracer.Start(ctx, SpanStatement)
+		err := invoker(ctx, inv)
+		span.SetTag(TagSqlId, inv.SqlId)
+		span.SetTag(TagAction, inv.Action)
+		span.SetTag(TagDriver, inv.Driver)
+		if inv.Metadata != nil {
+			span.SetTag(TagStatement, inv.Metadata.PrepareSql)
+			if inv.Action == "" {
+				span.SetTag(TagAction, inv.Metadata.Action)
+			}
+		}
+		span.SetTag(TagRowsAffected, inv.RowsAffected)
+		span.Finish(err)
+		return err
+	}
+}
+
+// traceTx 追踪事务的开启、提交以及回滚，savepoint不为空表示嵌套事务的保存点
+func (s *Session) traceTx(ctx context.Context, name, savepoint string, f func(ctx context.Context) error) error {
+	tracer := s.getTracer()
+	if tracer == NoopTracer {
+		return f(ctx)
+	}
+	ctx, span := tracer.Start(ctx, name)
+	span.SetTag(TagDriver, s.driver)
+	if savepoint != "" {
+		span.SetTag(TagSavepoint, savepoint)
+	}
+	err := f(ctx)
+	span.Finish(err)
+	return err
+}
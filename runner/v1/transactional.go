@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"github.com/xfali/gobatis/v2/errors"
+	"time"
+)
+
+// Propagation 事务传播方式
+type Propagation int
+
+const (
+	// PropagationRequired 存在事务则加入，否则新建事务
+	PropagationRequired Propagation = iota
+	// PropagationRequiresNew 总是新建事务，已存在的事务在执行期间被挂起
+	PropagationRequiresNew
+	// PropagationSupports 存在事务则加入，否则以非事务方式执行
+	PropagationSupports
+	// PropagationNotSupported 以非事务方式执行，已存在的事务在执行期间被挂起
+	PropagationNotSupported
+	// PropagationMandatory 必须在已存在的事务中执行，否则返回错误
+	PropagationMandatory
+	// PropagationNever 以非事务方式执行，存在事务则返回错误
+	PropagationNever
+)
+
+type TxOptions struct {
+	// Propagation 事务传播方式，默认为PropagationRequired，只对SessionManager.Transactional生效
+	Propagation Propagation
+	// Isolation 隔离级别，如sql.LevelReadCommitted、sql.LevelRepeatableRead、sql.LevelSerializable
+	// 默认使用数据库的隔离级别
+	Isolation sql.IsolationLevel
+	// ReadOnly 只读事务，事务中执行insert、update、delete语句返回errors.TransactionReadOnly
+	ReadOnly bool
+	// Timeout 事务超时时间，超时后事务中的语句被取消，0表示不限制
+	Timeout time.Duration
+}
+
+// Transactional 按照传播方式执行fn
+// fn的ctx中包含了本次执行使用的session，内层通过FindSession(ctx)获得session即可加入同一个事务
+// 新建的事务在fn返回nil时提交，返回error或者panic时回滚
+func (sm *SessionManager) Transactional(ctx context.Context, opts *TxOptions, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+
+	cur := FindSession(ctx)
+	inTx := cur != nil && cur.InTx()
+	switch opts.Propagation {
+	case PropagationRequired:
+		if inTx {
+			return fn(ctx)
+		}
+		return sm.runWithNewTx(ctx, opts, fn)
+	case PropagationRequiresNew:
+		return sm.runWithNewTx(ctx, opts, fn)
+	case PropagationSupports:
+		if cur != nil {
+			return fn(ctx)
+		}
+		return sm.runWithoutTx(ctx, fn)
+	case PropagationNotSupported:
+		if cur != nil && !inTx {
+			return fn(ctx)
+		}
+		return sm.runWithoutTx(ctx, fn)
+	case PropagationMandatory:
+		if !inTx {
+			return errors.TransactionRequired
+		}
+		return fn(ctx)
+	case PropagationNever:
+		if inTx {
+			return errors.TransactionNotAllowed
+		}
+		if cur != nil {
+			return fn(ctx)
+		}
+		return sm.runWithoutTx(ctx, fn)
+	}
+	return errors.ObjectNotSupport
+}
+
+func (sm *SessionManager) runWithNewTx(ctx context.Context, opts *TxOptions, fn func(ctx context.Context) error) error {
+	sess, err := sm.createSession(ctx)
+	if err != nil {
+		sm.logger.Errorln(err)
+		return err
+	}
+	defer sm.closeSession(sess)
+
+	txCtx := WithSession(ctx, sess)
+	sess.SetContext(txCtx)
+	return sess.TxWithOptions(txCtx, opts, func(session *Session) error {
+		return fn(txCtx)
+	})
+}
+
+func (sm *SessionManager) runWithoutTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := sm.createSession(ctx)
+	if err != nil {
+		sm.logger.Errorln(err)
+		return err
+	}
+	defer sm.closeSession(sess)
+
+	sessCtx := WithSession(ctx, sess)
+	sess.SetContext(sessCtx)
+	return fn(sessCtx)
+}
+
+func (sm *SessionManager) closeSession(sess *Session) {
+	if err := sess.Close(); err != nil {
+		sm.logger.Warnln(err)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"github.com/xfali/gobatis/v2/errors"
+	"testing"
+)
+
+func TestTransactional(t *testing.T) {
+	if FindSession(context.Background()) != nil {
+		t.Fatal("expect nil session")
+	}
+
+	t.Run("required join", func(t *testing.T) {
+		conn := &testConnection{}
+		sm := newTestSessionManager(conn, "mysql")
+		err := sm.Transactional(context.Background(), nil, func(ctx context.Context) error {
+			outer := FindSession(ctx)
+			return sm.Transactional(ctx, &TxOptions{Propagation: PropagationRequired}, func(ctx context.Context) error {
+				if FindSession(ctx) != outer {
+					t.Fatal("expect same session")
+				}
+				return sm.Transactional(ctx, &TxOptions{Propagation: PropagationMandatory}, func(ctx context.Context) error {
+					return nil
+				})
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conn.sessions) != 1 || len(conn.sessions[0].executed) != 2 {
+			t.Fatal("expect one transaction but get ", conn.sessions)
+		}
+	})
+
+	t.Run("requires new", func(t *testing.T) {
+		conn := &testConnection{}
+		sm := newTestSessionManager(conn, "mysql")
+		err := sm.Transactional(context.Background(), nil, func(ctx context.Context) error {
+			outer := FindSession(ctx)
+			return sm.Transactional(ctx, &TxOptions{Propagation: PropagationRequiresNew}, func(ctx context.Context) error {
+				inner := FindSession(ctx)
+				if inner == outer || !inner.InTx() {
+					t.Fatal("expect new transaction session")
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conn.sessions) != 2 {
+			t.Fatal("expect 2 sessions but get ", len(conn.sessions))
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		conn := &testConnection{}
+		sm := newTestSessionManager(conn, "mysql")
+		err := sm.Transactional(context.Background(), nil, func(ctx context.Context) error {
+			return sm.Transactional(ctx, &TxOptions{Propagation: PropagationNotSupported}, func(ctx context.Context) error {
+				if FindSession(ctx).InTx() {
+					t.Fatal("expect session without transaction")
+				}
+				return sm.Transactional(ctx, &TxOptions{Propagation: PropagationNever}, func(ctx context.Context) error {
+					return nil
+				})
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("mandatory", func(t *testing.T) {
+		sm := newTestSessionManager(&testConnection{}, "mysql")
+		err := sm.Transactional(context.Background(), &TxOptions{Propagation: PropagationMandatory}, func(ctx context.Context) error {
+			return nil
+		})
+		if err != errors.TransactionRequired {
+			t.Fatal("expect TransactionRequired but get ", err)
+		}
+	})
+
+	t.Run("never", func(t *testing.T) {
+		sm := newTestSessionManager(&testConnection{}, "mysql")
+		err := sm.Transactional(context.Background(), nil, func(ctx context.Context) error {
+			return sm.Transactional(ctx, &TxOptions{Propagation: PropagationNever}, func(ctx context.Context) error {
+				return nil
+			})
+		})
+		if err != errors.TransactionNotAllowed {
+			t.Fatal("expect TransactionNotAllowed but get ", err)
+		}
+	})
+}
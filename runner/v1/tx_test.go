@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"errors"
+	gerrors "github.com/xfali/gobatis/v2/errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNestedTx(t *testing.T) {
+	ctx := context.Background()
+	t.Run("release", func(t *testing.T) {
+		ts := newTestSession(nil)
+		sess := newTestSqlSession(ts, "mysql")
+		err := sess.Tx(ctx, func(session *Session) error {
+			return session.Tx(ctx, func(session *Session) error {
+				if !session.InTx() {
+					t.Fatal("expect in transaction")
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := []string{"BEGIN", "SAVEPOINT gobatis_sp_1", "RELEASE SAVEPOINT gobatis_sp_1", "COMMIT"}
+		if !reflect.DeepEqual(ts.executed, expect) {
+			t.Fatal("expect ", expect, " but get ", ts.executed)
+		}
+		if sess.InTx() {
+			t.Fatal("expect transaction finished")
+		}
+	})
+
+	t.Run("rollback inner", func(t *testing.T) {
+		ts := newTestSession(nil)
+		sess := newTestSqlSession(ts, "sqlserver")
+		innerErr := errors.New("inner")
+		err := sess.Tx(ctx, func(session *Session) error {
+			if e := session.Tx(ctx, func(session *Session) error {
+				return innerErr
+			}); e != innerErr {
+				t.Fatal("expect inner error but get ", e)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := []string{"BEGIN", "SAVE TRANSACTION gobatis_sp_1", "ROLLBACK TRANSACTION gobatis_sp_1", "COMMIT"}
+		if !reflect.DeepEqual(ts.executed, expect) {
+			t.Fatal("expect ", expect, " but get ", ts.executed)
+		}
+	})
+
+	t.Run("manual", func(t *testing.T) {
+		ts := newTestSession(nil)
+		sess := newTestSqlSession(ts, "postgres")
+		if err := sess.Savepoint(ctx, "sp"); err == nil {
+			t.Fatal("expect error without transaction")
+		}
+		err := sess.Tx(ctx, func(session *Session) error {
+			if err := session.Savepoint(ctx, "sp; DROP TABLE x"); err == nil {
+				t.Fatal("expect invalid name error")
+			}
+			if err := session.Savepoint(ctx, "sp"); err != nil {
+				return err
+			}
+			return session.RollbackToSavepoint(ctx, "sp")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := []string{"BEGIN", "SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp", "COMMIT"}
+		if !reflect.DeepEqual(ts.executed, expect) {
+			t.Fatal("expect ", expect, " but get ", ts.executed)
+		}
+	})
+}
+
+func TestTxOptions(t *testing.T) {
+	ctx := context.Background()
+	t.Run("read only", func(t *testing.T) {
+		sess := newTestSqlSession(newTestSession(nil), "mysql")
+		err := sess.TxWithOptions(ctx, &TxOptions{ReadOnly: true}, func(session *Session) error {
+			if err := session.Select("SELECT id FROM tbl_user").Param().Result(&[]testRow{}); err != nil {
+				return err
+			}
+			return session.Update("UPDATE tbl_user SET name = 'x'").Param().Result(nil)
+		})
+		if !errors.Is(err, gerrors.TransactionReadOnly) {
+			t.Fatal("expect TransactionReadOnly but get ", err)
+		}
+		if err := sess.Update("UPDATE tbl_user SET name = 'x'").Param().Result(nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		sess := newTestSqlSession(newTestSession(nil), "mysql")
+		err := sess.TxWithOptions(ctx, &TxOptions{Timeout: time.Millisecond}, func(session *Session) error {
+			if _, ok := session.GetContext().Deadline(); !ok {
+				t.Fatal("expect deadline")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := sess.GetContext().Deadline(); ok {
+			t.Fatal("expect session context restored")
+		}
+	})
+}
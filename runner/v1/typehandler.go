@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"github.com/xfali/lean/mapping"
+	"reflect"
+	"strings"
+)
+
+// typeHandlerResultMap 结果类型包含需要使用处理器的字段时，返回自动映射并包含这些字段的resultMap
+// 否则返回nil，按照列名映射
+func typeHandlerResultMap(bean interface{}) *parser.ResultMap {
+	t := reflect.TypeOf(bean)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || mapping.TimeType.AssignableTo(t) {
+		return nil
+	}
+	rm := &parser.ResultMap{AutoMapping: true}
+	if ret := withTypeHandlerResults(rm, t); ret != rm {
+		return ret
+	}
+	return nil
+}
+
+// withTypeHandlerResults 为resultMap补充需要使用处理器的字段映射，列名为column tag或者字段名
+// 没有需要补充的字段时返回rm本身
+func withTypeHandlerResults(rm *parser.ResultMap, t reflect.Type) *parser.ResultMap {
+	var results []parser.ResultMapping
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := f.Tag.Get(typehandler.TagName)
+		if name == "" && !typehandler.Has(f.Type) {
+			continue
+		}
+		column := f.Tag.Get(mapping.FieldAliasTagName)
+		if column == "" {
+			column = f.Name
+		}
+		if mappedResult(rm.Results, f.Name, column) {
+			continue
+		}
+		results = append(results, parser.ResultMapping{
+			Property:    f.Name,
+			Column:      column,
+			TypeHandler: name,
+		})
+	}
+	if len(results) == 0 {
+		return rm
+	}
+	ret := *rm
+	ret.Results = append(append([]parser.ResultMapping(nil), rm.Results...), results...)
+	return &ret
+}
+
+func mappedResult(results []parser.ResultMapping, property, column string) bool {
+	for _, r := range results {
+		if strings.EqualFold(r.Property, property) || strings.EqualFold(r.Column, column) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/xfali/gobatis/v2/parsing/manager"
+	"github.com/xfali/gobatis/v2/parsing/parser"
+	"github.com/xfali/gobatis/v2/typehandler"
+	"reflect"
+	"testing"
+)
+
+type grade int
+
+type address struct {
+	City   string `json:"city"`
+	Street string `json:"street"`
+}
+
+type student struct {
+	Id      int64
+	Grade   grade
+	Address address  `column:"addr" typeHandler:"json"`
+	Tags    []string `typeHandler:"csv"`
+}
+
+func init() {
+	grades := map[grade]string{1: "A", 2: "B"}
+	typehandler.Register(typehandler.TypeOf[grade](), typehandler.Func(func(v grade) (interface{}, error) {
+		return grades[v], nil
+	}, func(src interface{}) (grade, error) {
+		s := fmt.Sprintf("%s", src)
+		for k, v := range grades {
+			if v == s {
+				return k, nil
+			}
+		}
+		return 0, fmt.Errorf("unknown grade %s", s)
+	}))
+	registerResultMap(&parser.ResultMap{
+		Id:          "test.studentMap",
+		AutoMapping: true,
+		Results: []parser.ResultMapping{
+			{Property: "Tags", Column: "labels", TypeHandler: typehandler.CSVName},
+		},
+	})
+}
+
+func TestTypeHandlerScan(t *testing.T) {
+	t.Run("auto", func(t *testing.T) {
+		ret, _ := newTestSession([]string{"Id", "Grade", "addr", "Tags"},
+			[]interface{}{int64(1), []byte("A"), []byte(`{"city":"a","street":"b"}`), "x,y"},
+			[]interface{}{int64(2), "B", nil, ""}).Query(context.Background(), "")
+		var ss []student
+		n, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &ss, ret, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := []student{
+			{Id: 1, Grade: 1, Address: address{City: "a", Street: "b"}, Tags: []string{"x", "y"}},
+			{Id: 2, Grade: 2, Tags: []string{}},
+		}
+		if n != 2 || !reflect.DeepEqual(ss, expect) {
+			t.Fatalf("unexpected result %+v", ss)
+		}
+	})
+	t.Run("resultMap", func(t *testing.T) {
+		ret, _ := newTestSession([]string{"Id", "Grade", "labels"},
+			[]interface{}{int64(1), "B", "x"}).Query(context.Background(), "")
+		var s student
+		if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &s, ret, "test.studentMap"); err != nil {
+			t.Fatal(err)
+		}
+		if s.Id != 1 || s.Grade != 2 || !reflect.DeepEqual(s.Tags, []string{"x"}) {
+			t.Fatalf("unexpected result %+v", s)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		ret, _ := newTestSession([]string{"Grade"}, []interface{}{"C"}).Query(context.Background(), "")
+		var s student
+		if _, err := scanRows(context.Background(), newTestSqlSession(nil, "mysql"), &s, ret, ""); err == nil || err.Error() != "unknown grade C" {
+			t.Fatal("expect handler error but get ", err)
+		}
+	})
+}
+
+func TestTypeHandlerBind(t *testing.T) {
+	s := student{Id: 1, Grade: 2, Address: address{City: "a"}, Tags: []string{"x", "y"}}
+	expect := []interface{}{"B", `{"city":"a","street":""}`, "x,y", `["x","y"]`}
+
+	xm, _ := manager.GetGlobalManagerRegistry().FindManager("xml")
+	p, err := xm.CreateDynamicStatementParser("UPDATE student SET grade = #{student.Grade}, addr = #{student.Address}, tags = #{student.Tags}, raw = #{student.Tags, typeHandler=json} WHERE id = #{student.Id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := p.ParseMetadata("mysql", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.PrepareSql != "UPDATE student SET grade = ?, addr = ?, tags = ?, raw = ? WHERE id = ?" ||
+		!reflect.DeepEqual(md.Params, append(expect, int64(1))) {
+		t.Fatalf("unexpected metadata %s %v", md.PrepareSql, md.Params)
+	}
+
+	tm, _ := manager.GetGlobalManagerRegistry().FindManager("tpl")
+	p, err = tm.CreateDynamicStatementParser(`UPDATE student SET grade = {{arg .Grade}}, addr = {{arg .Address "json"}}, tags = {{arg .Tags "csv"}}, raw = {{arg .Tags "json"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err = p.ParseMetadata("mysql", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.PrepareSql != "UPDATE student SET grade = ?, addr = ?, tags = ?, raw = ?" || !reflect.DeepEqual(md.Params, expect) {
+		t.Fatalf("unexpected metadata %s %v", md.PrepareSql, md.Params)
+	}
+
+	// 与xml相同，模板中的字段使用typeHandler tag指定的处理器，显式指定的处理器优先
+	p, err = tm.CreateDynamicStatementParser(`UPDATE student SET grade = {{arg .Grade}}, addr = {{arg .Address}}, tags = {{arg .Tags}}, raw = {{arg .Tags "json"}}{{with .}} WHERE id = {{arg .Id}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err = p.ParseMetadata("mysql", &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.PrepareSql != "UPDATE student SET grade = ?, addr = ?, tags = ?, raw = ? WHERE id = ?" ||
+		!reflect.DeepEqual(md.Params, append(expect, int64(1))) {
+		t.Fatalf("unexpected metadata %s %v", md.PrepareSql, md.Params)
+	}
+}
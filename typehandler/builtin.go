@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package typehandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/reflection"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+)
+
+const (
+	// JSONName 使用json编码的处理器名称
+	JSONName = "json"
+	// CSVName 使用逗号分隔列表的处理器名称
+	CSVName = "csv"
+)
+
+var (
+	// JSON 将参数编码为json字符串，解析结果时将json解码到目标值
+	JSON TypeHandler = jsonHandler{}
+	// CSV 将slice参数编码为逗号分隔的字符串，解析结果时按照逗号拆分后转换为slice元素
+	CSV TypeHandler = csvHandler{}
+)
+
+type jsonHandler struct{}
+
+func (jsonHandler) Bind(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (jsonHandler) Scan(src interface{}, dst reflect.Value) error {
+	b, err := toBytes(src)
+	if err != nil {
+		return err
+	}
+	pv := reflect.New(dst.Type())
+	if err := json.Unmarshal(b, pv.Interface()); err != nil {
+		return err
+	}
+	dst.Set(pv.Elem())
+	return nil
+}
+
+type csvHandler struct{}
+
+func (csvHandler) Bind(value interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		xlog.Warnf("csv type handler cannot bind %s\n", rv.Type())
+		return nil, errors.TypeHandlerValueInvalid
+	}
+	items := make([]string, rv.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(items, ","), nil
+}
+
+func (csvHandler) Scan(src interface{}, dst reflect.Value) error {
+	if dst.Kind() != reflect.Slice {
+		xlog.Warnf("csv type handler cannot scan into %s\n", dst.Type())
+		return errors.TypeHandlerValueInvalid
+	}
+	b, err := toBytes(src)
+	if err != nil {
+		return err
+	}
+	var items []string
+	if len(b) > 0 {
+		items = strings.Split(string(b), ",")
+	}
+	ret := reflect.MakeSlice(dst.Type(), len(items), len(items))
+	for i, item := range items {
+		if !reflection.SetValue(ret.Index(i), reflect.ValueOf(strings.TrimSpace(item))) {
+			xlog.Warnf("csv type handler cannot convert %s to %s\n", item, dst.Type().Elem())
+			return errors.TypeHandlerValueInvalid
+		}
+	}
+	dst.Set(ret)
+	return nil
+}
+
+func toBytes(src interface{}) ([]byte, error) {
+	switch v := src.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	xlog.Warnf("type handler cannot convert %T to bytes\n", src)
+	return nil, errors.TypeHandlerValueInvalid
+}
+
+type funcHandler[T any] struct {
+	bind func(T) (interface{}, error)
+	scan func(interface{}) (T, error)
+}
+
+// Func 使用函数创建T类型的处理器，用于枚举、decimal、uuid等自定义类型
+func Func[T any](bind func(v T) (interface{}, error), scan func(src interface{}) (T, error)) TypeHandler {
+	return funcHandler[T]{bind: bind, scan: scan}
+}
+
+// TypeOf 获得T的类型，用于注册处理器
+func TypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (h funcHandler[T]) Bind(value interface{}) (interface{}, error) {
+	v, ok := value.(T)
+	if !ok {
+		xlog.Warnf("type handler of %s cannot bind %T\n", TypeOf[T](), value)
+		return nil, errors.TypeHandlerValueInvalid
+	}
+	return h.bind(v)
+}
+
+func (h funcHandler[T]) Scan(src interface{}, dst reflect.Value) error {
+	v, err := h.scan(src)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	if !rv.Type().AssignableTo(dst.Type()) {
+		xlog.Warnf("type handler of %s cannot scan into %s\n", rv.Type(), dst.Type())
+		return errors.TypeHandlerValueInvalid
+	}
+	dst.Set(rv)
+	return nil
+}
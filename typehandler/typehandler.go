@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package typehandler
+
+import (
+	"github.com/xfali/gobatis/v2/errors"
+	"github.com/xfali/xlog"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TagName 字段tag，指定参数绑定以及结果解析使用的命名处理器，如`typeHandler:"json"`
+const TagName = "typeHandler"
+
+// TypeHandler 参数绑定以及结果解析时的类型转换
+type TypeHandler interface {
+	// Bind 将参数转换为数据库驱动支持的值
+	Bind(value interface{}) (interface{}, error)
+	// Scan 将数据库返回的值转换后写入dst，dst可以设置
+	Scan(src interface{}, dst reflect.Value) error
+}
+
+// Value 指定了处理器名称的参数值，解析struct参数时由TagName生成
+type Value struct {
+	Handler string
+	Value   interface{}
+}
+
+type typeKey struct {
+	t        reflect.Type
+	jdbcType string
+}
+
+var (
+	gTypeHandlers  = map[typeKey]TypeHandler{}
+	gNamedHandlers = map[string]TypeHandler{
+		JSONName: JSON,
+		CSVName:  CSV,
+	}
+	gLock sync.RWMutex
+)
+
+// Register 注册Go类型使用的处理器
+func Register(t reflect.Type, h TypeHandler) {
+	RegisterJdbcType(t, "", h)
+}
+
+// RegisterJdbcType 注册Go类型在指定jdbcType时使用的处理器，优先于只按照类型注册的处理器
+func RegisterJdbcType(t reflect.Type, jdbcType string, h TypeHandler) {
+	gLock.Lock()
+	defer gLock.Unlock()
+
+	key := typeKey{t: t, jdbcType: strings.ToUpper(jdbcType)}
+	if _, ok := gTypeHandlers[key]; ok {
+		xlog.Warnf("type handler of %s jdbcType %s is replaced\n", t, jdbcType)
+	}
+	gTypeHandlers[key] = h
+}
+
+// RegisterName 注册命名的处理器，在struct tag、xml的typeHandler属性或者#{name,typeHandler=xxx}中引用
+func RegisterName(name string, h TypeHandler) {
+	gLock.Lock()
+	defer gLock.Unlock()
+
+	if _, ok := gNamedHandlers[name]; ok {
+		xlog.Warnf("type handler %s is replaced\n", name)
+	}
+	gNamedHandlers[name] = h
+}
+
+// Find 按照Go类型以及jdbcType查找处理器，jdbcType没有匹配时使用只按照类型注册的处理器
+func Find(t reflect.Type, jdbcType string) (TypeHandler, bool) {
+	if t == nil {
+		return nil, false
+	}
+	gLock.RLock()
+	defer gLock.RUnlock()
+
+	if jdbcType != "" {
+		if h, ok := gTypeHandlers[typeKey{t: t, jdbcType: strings.ToUpper(jdbcType)}]; ok {
+			return h, true
+		}
+	}
+	h, ok := gTypeHandlers[typeKey{t: t}]
+	return h, ok
+}
+
+// FindName 查找命名的处理器
+func FindName(name string) (TypeHandler, bool) {
+	gLock.RLock()
+	defer gLock.RUnlock()
+
+	h, ok := gNamedHandlers[name]
+	return h, ok
+}
+
+// Resolve 选择处理器：name不为空时使用命名处理器，否则按照类型以及jdbcType查找
+// 没有匹配的处理器时返回nil
+func Resolve(t reflect.Type, name, jdbcType string) (TypeHandler, error) {
+	if name != "" {
+		h, ok := FindName(name)
+		if !ok {
+			xlog.Warnf("type handler %s not found\n", name)
+			return nil, errors.TypeHandlerNotFound
+		}
+		return h, nil
+	}
+	h, _ := Find(t, jdbcType)
+	return h, nil
+}
+
+// Unwrap 获得Value中的原始参数值
+func Unwrap(value interface{}) interface{} {
+	if v, ok := value.(Value); ok {
+		return v.Value
+	}
+	return value
+}
+
+// Bind 转换绑定参数，没有匹配的处理器时返回原值
+// name为空时使用Value中指定的处理器
+func Bind(value interface{}, name, jdbcType string) (interface{}, error) {
+	if v, ok := value.(Value); ok {
+		if name == "" {
+			name = v.Handler
+		}
+		value = v.Value
+	}
+	if value == nil {
+		return nil, nil
+	}
+	h, err := Resolve(reflect.TypeOf(value), name, jdbcType)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Ptr {
+			return value, nil
+		}
+		if h, _ = Find(rv.Type().Elem(), jdbcType); h == nil {
+			return value, nil
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		value = rv.Elem().Interface()
+	}
+	return h.Bind(value)
+}
+
+// Scan 使用处理器将src转换后写入dst，没有匹配的处理器时返回false
+// 没有指定name时按照dst的类型查找，dst为指针时同时查找指针指向的类型
+func Scan(src interface{}, dst reflect.Value, name, jdbcType string) (bool, error) {
+	h, err := Resolve(dst.Type(), name, jdbcType)
+	if err != nil {
+		return false, err
+	}
+	if h != nil {
+		return true, h.Scan(src, dst)
+	}
+	if dst.Kind() != reflect.Ptr {
+		return false, nil
+	}
+	if h, _ = Find(dst.Type().Elem(), jdbcType); h == nil {
+		return false, nil
+	}
+	pv := reflect.New(dst.Type().Elem())
+	if err := h.Scan(src, pv.Elem()); err != nil {
+		return true, err
+	}
+	dst.Set(pv)
+	return true, nil
+}
+
+// Has 类型是否注册了处理器，包括指针指向的类型
+func Has(t reflect.Type) bool {
+	if _, ok := Find(t, ""); ok {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		_, ok := Find(t.Elem(), "")
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2025, Xiongfa Li.
+ * All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package typehandler
+
+import (
+	"fmt"
+	"github.com/xfali/gobatis/v2/errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type level int
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func init() {
+	levels := []string{"low", "middle", "high"}
+	Register(TypeOf[level](), Func(func(v level) (interface{}, error) {
+		return levels[v], nil
+	}, func(src interface{}) (level, error) {
+		for i, s := range levels {
+			if s == fmt.Sprintf("%s", src) {
+				return level(i), nil
+			}
+		}
+		return 0, errors.TypeHandlerValueInvalid
+	}))
+	RegisterJdbcType(TypeOf[level](), "integer", Func(func(v level) (interface{}, error) {
+		return int64(v), nil
+	}, func(src interface{}) (level, error) {
+		return level(src.(int64)), nil
+	}))
+}
+
+func TestBind(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		name     string
+		jdbcType string
+		expect   interface{}
+	}{
+		{value: 1, expect: 1},
+		{value: level(2), expect: "high"},
+		{value: level(2), jdbcType: "INTEGER", expect: int64(2)},
+		{value: level(1), jdbcType: "VARCHAR", expect: "middle"},
+		{value: &point{1, 2}, name: JSONName, expect: `{"x":1,"y":2}`},
+		{value: Value{Handler: JSONName, Value: point{3, 4}}, expect: `{"x":3,"y":4}`},
+		{value: Value{Handler: JSONName, Value: []int{1}}, name: CSVName, expect: "1"},
+		{value: []string{"a", "b"}, name: CSVName, expect: "a,b"},
+		{value: (*level)(nil), expect: nil},
+		{value: nil, name: JSONName, expect: nil},
+	}
+	for i, c := range cases {
+		v, err := Bind(c.value, c.name, c.jdbcType)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if !reflect.DeepEqual(v, c.expect) {
+			t.Fatalf("case %d expect %v but get %v", i, c.expect, v)
+		}
+	}
+
+	if _, err := Bind(1, "notExist", ""); err != errors.TypeHandlerNotFound {
+		t.Fatal("expect TypeHandlerNotFound but get ", err)
+	}
+	if _, err := Bind(1, CSVName, ""); err != errors.TypeHandlerValueInvalid {
+		t.Fatal("expect TypeHandlerValueInvalid but get ", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	var s struct {
+		Level    level
+		LevelPtr *level
+		Point    point
+		Ids      []int64
+		Name     string
+	}
+	v := reflect.ValueOf(&s).Elem()
+	scan := func(field string, src interface{}, name, jdbcType string) bool {
+		ok, err := Scan(src, v.FieldByName(field), name, jdbcType)
+		if err != nil {
+			t.Fatal(field, err)
+		}
+		return ok
+	}
+	if !scan("Level", []byte("middle"), "", "") || s.Level != 1 {
+		t.Fatal("unexpected level ", s.Level)
+	}
+	if !scan("Level", int64(2), "", "integer") || s.Level != 2 {
+		t.Fatal("unexpected level ", s.Level)
+	}
+	if !scan("LevelPtr", "high", "", "") || s.LevelPtr == nil || *s.LevelPtr != 2 {
+		t.Fatal("unexpected level pointer ", s.LevelPtr)
+	}
+	if !scan("Point", `{"x":1,"y":2}`, JSONName, "") || s.Point != (point{1, 2}) {
+		t.Fatal("unexpected point ", s.Point)
+	}
+	if !scan("Ids", []byte("1, 2,3"), CSVName, "") || !reflect.DeepEqual(s.Ids, []int64{1, 2, 3}) {
+		t.Fatal("unexpected ids ", s.Ids)
+	}
+	if scan("Name", "a", "", "") || s.Name != "" {
+		t.Fatal("expect no handler for string")
+	}
+
+	if _, err := Scan("a,b", v.FieldByName("Ids"), CSVName, ""); err != errors.TypeHandlerValueInvalid {
+		t.Fatal("expect TypeHandlerValueInvalid but get ", err)
+	}
+	if _, err := Scan(1, v.FieldByName("Point"), JSONName, ""); err != errors.TypeHandlerValueInvalid {
+		t.Fatal("expect TypeHandlerValueInvalid but get ", err)
+	}
+}
+
+func TestRegisterName(t *testing.T) {
+	RegisterName("upper", Func(func(v string) (interface{}, error) {
+		return strings.ToUpper(v), nil
+	}, func(src interface{}) (string, error) {
+		return strings.ToLower(fmt.Sprintf("%s", src)), nil
+	}))
+	if v, err := Bind("abc", "upper", ""); err != nil || v != "ABC" {
+		t.Fatal("unexpected bind result ", v, err)
+	}
+	var s string
+	if ok, err := Scan([]byte("ABC"), reflect.ValueOf(&s).Elem(), "upper", ""); !ok || err != nil || s != "abc" {
+		t.Fatal("unexpected scan result ", s, err)
+	}
+	if _, err := Bind(1, "upper", ""); err != errors.TypeHandlerValueInvalid {
+		t.Fatal("expect TypeHandlerValueInvalid but get ", err)
+	}
+}